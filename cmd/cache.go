@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+)
+
+const cacheUsage = "usage: cache stats | list [prefix] | purge <url|prefix> | clear"
+
+func commandCache(ctx context.Context, c *config.Config) error {
+	if len(c.Args) == 0 {
+		return writeLine(c, cacheUsage)
+	}
+	switch c.Args[0] {
+	case "stats":
+		return cacheStats(c)
+	case "list":
+		prefix := ""
+		if len(c.Args) > 1 {
+			prefix = c.Args[1]
+		}
+		return cacheList(c, prefix)
+	case "purge":
+		if len(c.Args) < 2 {
+			return writeLine(c, cacheUsage)
+		}
+		return cachePurge(c, c.Args[1])
+	case "clear":
+		if err := c.Cache.Clear(); err != nil {
+			c.Logger.Error("Error clearing cache", "error", err)
+			return err
+		}
+		return writeLine(c, "Cache cleared")
+	default:
+		return writeLine(c, cacheUsage)
+	}
+}
+
+func cacheStats(c *config.Config) error {
+	entries, err := c.Cache.List("")
+	if err != nil {
+		c.Logger.Error("Error listing cache", "error", err)
+		return err
+	}
+	size := 0
+	var oldest domain.CacheEntry
+	for _, entry := range entries {
+		size += entry.Size
+		if oldest.Key == "" || entry.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = entry
+		}
+	}
+	_, err = fmt.Fprintf(c.Out, "Entries: %d\nSize: %s\n", len(entries), formatBytes(size))
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	hitRatio := "n/a"
+	compression := "n/a"
+	revalidations := "n/a"
+	evictions := "n/a"
+	if reporter, ok := c.Cache.(domain.StatsReporter); ok {
+		stats := reporter.Stats()
+		revalidations = fmt.Sprintf("%d", stats.Revalidations)
+		evictions = fmt.Sprintf("%d expired, %d over capacity", stats.EvictedTTL, stats.EvictedCapacity)
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			hitRatio = fmt.Sprintf("%.1f%% (%d of %d lookups)", 100*float64(stats.Hits)/float64(lookups), stats.Hits, lookups)
+		}
+		if stats.StoredBytes > 0 {
+			compression = fmt.Sprintf("%.1fx (%s stored for %s)",
+				float64(stats.UncompressedBytes)/float64(stats.StoredBytes),
+				formatBytes(int(stats.StoredBytes)), formatBytes(int(stats.UncompressedBytes)))
+		}
+	}
+	_, err = fmt.Fprintf(c.Out, "Hit ratio: %s\nRevalidations: %s\nEvictions: %s\nCompression: %s\n",
+		hitRatio, revalidations, evictions, compression)
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	if oldest.Key == "" {
+		return nil
+	}
+	_, err = fmt.Fprintf(c.Out, "Oldest entry: %s (cached %s)\n", oldest.Key, oldest.CreatedAt.Format(time.DateTime))
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	return nil
+}
+
+func cacheList(c *config.Config, prefix string) error {
+	entries, err := c.Cache.List(prefix)
+	if err != nil {
+		c.Logger.Error("Error listing cache", "error", err)
+		return err
+	}
+	for _, entry := range entries {
+		expires := "never"
+		if !entry.ExpiresAt.IsZero() {
+			expires = entry.ExpiresAt.Format(time.DateTime)
+		}
+		_, err = fmt.Fprintf(c.Out, "  %s (%s, expires %s)\n", entry.Key, formatBytes(entry.Size), expires)
+		if err != nil {
+			c.Logger.Error("Error writing response: ", "error", err)
+			return err
+		}
+	}
+	return writeLine(c, fmt.Sprintf("%d entries", len(entries)))
+}
+
+func cachePurge(c *config.Config, prefix string) error {
+	removed, err := c.Cache.Purge(prefix)
+	if err != nil {
+		c.Logger.Error("Error purging cache", "prefix", prefix, "error", err)
+		return err
+	}
+	return writeLine(c, fmt.Sprintf("Purged %d entries", removed))
+}
+
+func writeLine(c *config.Config, line string) error {
+	_, err := fmt.Fprintln(c.Out, line)
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	return nil
+}
+
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func newCacheCommand() *CliCommand {
+	return &CliCommand{
+		name:        "cache",
+		description: "Inspect or flush the response cache (stats, list [prefix], purge <url|prefix>, clear)",
+		Callback:    commandCache,
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokecache"
+)
+
+func TestCommandCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		args        []string
+		want        []string
+		wantMissing []string
+		wantKeys    int
+	}{
+		{name: "no subcommand", want: []string{cacheUsage}, wantKeys: 3},
+		{name: "unknown subcommand", args: []string{"flush"}, want: []string{cacheUsage}, wantKeys: 3},
+		{name: "purge without prefix", args: []string{"purge"}, want: []string{cacheUsage}, wantKeys: 3},
+		{
+			name:     "stats",
+			args:     []string{"stats"},
+			want:     []string{"Entries: 3", "Size: 23 B", "Hit ratio: 50.0% (1 of 2 lookups)", "Revalidations: 0", "Evictions: 0 expired, 0 over capacity", "Compression: 1.0x (23 B stored for 23 B)", "Oldest entry: https://pokeapi.co/api/v2/location-area/"},
+			wantKeys: 3,
+		},
+		{
+			name:        "list with prefix",
+			args:        []string{"list", "https://pokeapi.co/api/v2/pokemon/"},
+			want:        []string{"https://pokeapi.co/api/v2/pokemon/mew (3 B, expires never)", "https://pokeapi.co/api/v2/pokemon/pikachu", "2 entries"},
+			wantMissing: []string{"location-area"},
+			wantKeys:    3,
+		},
+		{
+			name:     "purge prefix",
+			args:     []string{"purge", "https://pokeapi.co/api/v2/pokemon/"},
+			want:     []string{"Purged 2 entries"},
+			wantKeys: 1,
+		},
+		{name: "clear", args: []string{"clear"}, want: []string{"Cache cleared"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cache := pokecache.NewCache(time.Hour, pokecache.WithTTLPolicy(pokecache.DefaultTTLPolicy(time.Hour)))
+			t.Cleanup(cache.Done)
+			for _, key := range []string{"location-area/", "pokemon/mew", "pokemon/pikachu"} {
+				body := strings.TrimPrefix(strings.TrimSuffix(key, "/"), "pokemon/")
+				if err := cache.Add("https://pokeapi.co/api/v2/"+key, []byte(body)); err != nil {
+					t.Fatalf("error adding key %s: %v", key, err)
+				}
+				time.Sleep(time.Millisecond)
+			}
+			_, _ = cache.Get("https://pokeapi.co/api/v2/pokemon/mew")
+			_, _ = cache.Get("https://pokeapi.co/api/v2/pokemon/missing")
+
+			out := &bytes.Buffer{}
+			c := config.Config{
+				Cache:  cache,
+				Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:    out,
+				Args:   tc.args,
+			}
+			if err := commandCache(context.Background(), &c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("expected output to contain %q, got %q", want, out.String())
+				}
+			}
+			for _, missing := range tc.wantMissing {
+				if strings.Contains(out.String(), missing) {
+					t.Fatalf("expected output not to contain %q, got %q", missing, out.String())
+				}
+			}
+			entries, err := cache.List("")
+			if err != nil || len(entries) != tc.wantKeys {
+				t.Fatalf("expected %d entries left, got %d (%v)", tc.wantKeys, len(entries), err)
+			}
+		})
+	}
+}
+
+// statsCache reports fixed counters.
+type statsCache struct {
+	*stubCache
+	stats domain.CacheStats
+}
+
+func (s statsCache) Stats() domain.CacheStats { return s.stats }
+
+func TestCacheStatsCounters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		cache domain.Cacher
+		want  []string
+	}{
+		{
+			name:  "counters",
+			cache: statsCache{stubCache: &stubCache{}, stats: domain.CacheStats{Hits: 3, Misses: 1, Revalidations: 4, EvictedTTL: 5, EvictedCapacity: 2}},
+			want:  []string{"Hit ratio: 75.0% (3 of 4 lookups)", "Revalidations: 4", "Evictions: 5 expired, 2 over capacity"},
+		},
+		{
+			name:  "no stats",
+			cache: &stubCache{},
+			want:  []string{"Hit ratio: n/a", "Revalidations: n/a", "Evictions: n/a"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			c := config.Config{
+				Cache:  tc.cache,
+				Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:    out,
+				Args:   []string{"stats"},
+			}
+			if err := commandCache(context.Background(), &c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("expected output to contain %q, got %q", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCommandCacheWriteError(t *testing.T) {
+	t.Parallel()
+	cache := pokecache.NewCache(time.Hour)
+	t.Cleanup(cache.Done)
+	c := config.Config{
+		Cache:  cache,
+		Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:    errWriter{},
+		Args:   []string{"stats"},
+	}
+	if err := commandCache(context.Background(), &c); err == nil {
+		t.Fatal("expected write error")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 0, want: "0 B"},
+		{n: 1023, want: "1023 B"},
+		{n: 1536, want: "1.5 KiB"},
+		{n: 5 << 20, want: "5.0 MiB"},
+	}
+	for _, tc := range tests {
+		if got := formatBytes(tc.n); got != tc.want {
+			t.Fatalf("formatBytes(%d): expected %q, got %q", tc.n, tc.want, got)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
 	"github.com/Flarenzy/Pokedex/internal/pokedex"
@@ -280,13 +282,23 @@ type PokemonFromAPI struct {
 	} `json:"past_types"`
 }
 
-func getPokemon(c *config.Config, url string) error {
-	body, err := getBodyWithCache(c, url)
+// Catches without a recorded encounter roll a level in this range.
+const (
+	defaultMinLevel = 2
+	defaultMaxLevel = 20
+)
+
+func getPokemon(ctx context.Context, c *config.Config, url string) error {
+	body, err := getBodyWithCache(ctx, c, url)
 	if err != nil {
 		return err
 	}
+	return catchPokemon(c, url, body)
+}
+
+func catchPokemon(c *config.Config, url string, body []byte) error {
 	var pokemonFromAPI PokemonFromAPI
-	err = json.Unmarshal(body, &pokemonFromAPI)
+	err := json.Unmarshal(body, &pokemonFromAPI)
 	if err != nil {
 		c.Logger.Error("Error parsing response: ", "url", url, "error", err)
 		return err
@@ -294,24 +306,19 @@ func getPokemon(c *config.Config, url string) error {
 	baseChance := 100.0
 	chanceToCatch := baseChance / (float64(pokemonFromAPI.BaseExperience) + baseChance)
 	if c.RandFloat64() < chanceToCatch {
-		caughtPokemon := pokedex.Pokemon{
-			Id:             pokemonFromAPI.Id,
-			Name:           pokemonFromAPI.Name,
-			BaseExperience: pokemonFromAPI.BaseExperience,
-			Height:         pokemonFromAPI.Height,
-			IsDefault:      pokemonFromAPI.IsDefault,
-			Order:          pokemonFromAPI.Order,
-			Weight:         pokemonFromAPI.Weight,
-			Abilities:      pokemonFromAPI.Abilities,
-			Stats:          pokemonFromAPI.Stats,
-			Types:          pokemonFromAPI.Types,
-		}
-		c.Pokedex.Add(caughtPokemon)
-		_, err = fmt.Fprintln(c.Out, caughtPokemon.Name, "was caught!")
+		caughtPokemon := pokemonFromAPI.toPokemon()
+		caughtPokemon.CaughtAt = time.Now()
+		caughtPokemon.Location, caughtPokemon.Level = rollEncounter(c, caughtPokemon.Name)
+		caughtPokemon.HeldItem = pokemonFromAPI.rollHeldItem(c.RandFloat64)
+		caughtPokemon = c.Pokedex.Add(caughtPokemon)
+		_, err = fmt.Fprintf(c.Out, "%v was caught! (#%d, level %d)\n", caughtPokemon.Name, caughtPokemon.InstanceID, caughtPokemon.Level)
 		if err != nil {
 			c.Logger.Error("Error writing response: ", "url", url, "error", err)
 			return err
 		}
+		if err = saveWithWarning(c); err != nil {
+			return err
+		}
 	} else {
 		_, err = fmt.Fprintln(c.Out, pokemonFromAPI.Name, "escaped!")
 		if err != nil {
@@ -323,29 +330,89 @@ func getPokemon(c *config.Config, url string) error {
 	return nil
 }
 
-func commandCatch(c *config.Config) error {
+// toPokemon keeps the parts of an API response that are stored in the
+// Pokedex. Instance fields such as level are filled in by the caller.
+func (p PokemonFromAPI) toPokemon() pokedex.Pokemon {
+	return pokedex.Pokemon{
+		Id:             p.Id,
+		Name:           p.Name,
+		BaseExperience: p.BaseExperience,
+		Height:         p.Height,
+		IsDefault:      p.IsDefault,
+		Order:          p.Order,
+		Weight:         p.Weight,
+		Abilities:      p.Abilities,
+		Stats:          p.Stats,
+		Types:          p.Types,
+		Moves:          p.Moves,
+		Species:        p.Species,
+		Sprites: pokedex.Sprites{
+			FrontDefault:    p.Sprites.FrontDefault,
+			FrontShiny:      p.Sprites.FrontShiny,
+			BackDefault:     p.Sprites.BackDefault,
+			OfficialArtwork: p.Sprites.Other.OfficialArtwork.FrontDefault,
+		},
+	}
+}
+
+// rollHeldItem gives each item PokeAPI lists its rarity, a percentage, as the
+// chance of being held, taking the highest rarity across game versions.
+// Items are tried in order and the first successful roll wins.
+func (p PokemonFromAPI) rollHeldItem(randFloat64 func() float64) string {
+	for _, held := range p.HeldItems {
+		rarity := 0
+		for _, v := range held.VersionDetails {
+			rarity = max(rarity, v.Rarity)
+		}
+		if rarity > 0 && randFloat64()*100 < float64(rarity) {
+			return held.Item.Name
+		}
+	}
+	return ""
+}
+
+// rollEncounter picks the location and level for a new catch from the last
+// explored area that had the species.
+func rollEncounter(c *config.Config, name string) (string, int) {
+	encounter, ok := c.Encounters[name]
+	if !ok || encounter.MaxLevel == 0 {
+		encounter.MinLevel, encounter.MaxLevel = defaultMinLevel, defaultMaxLevel
+	}
+	span := encounter.MaxLevel - encounter.MinLevel + 1
+	level := encounter.MinLevel + min(int(c.RandFloat64()*float64(span)), span-1)
+	return encounter.Area, level
+}
+
+func commandCatch(ctx context.Context, c *config.Config) error {
 	if len(c.Args) == 0 {
 		c.Logger.Info("No pokemon to catch")
 		return ErrNoPokemon
 	}
-	for _, arg := range c.Args {
-		url := c.PokemonURL + arg
-		_, err := fmt.Fprintf(c.Out, "Throwing a Pokeball at %v...\n", arg)
+	results := fetchAll(ctx, c, c.Args, func(arg string) string { return c.PokemonURL + arg })
+	var failures []fetchResult
+	for _, r := range results {
+		_, err := fmt.Fprintf(c.Out, "Throwing a Pokeball at %v...\n", r.arg)
 		if err != nil {
-			c.Logger.Error("Error writing response: ", "url", url, "error", err)
+			c.Logger.Error("Error writing response: ", "url", r.url, "error", err)
 			return err
 		}
-		err = getPokemon(c, url)
-		if err != nil {
-			c.Logger.Error("Error getting pokemon in area: ", "error", err)
+		if r.err == nil {
+			r.err = catchPokemon(c, r.url, r.body)
+		}
+		if r.err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.Logger.Error("Error getting pokemon: ", "url", r.url, "error", r.err)
+			failures = append(failures, r)
 		}
 		_, err = fmt.Fprintln(c.Out, "")
 		if err != nil {
-			c.Logger.Error("Error writing response: ", "url", url, "error", err)
+			c.Logger.Error("Error writing response: ", "url", r.url, "error", err)
 			return err
 		}
 	}
-	return nil
+	return reportFailures(c, len(results), failures)
 }
 
 func newCatchCommand() *CliCommand {
@@ -2,16 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Flarenzy/Pokedex/internal"
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
 	"github.com/Flarenzy/Pokedex/internal/logging"
 	"github.com/Flarenzy/Pokedex/internal/pokecache"
 	"github.com/Flarenzy/Pokedex/internal/pokedex"
@@ -61,10 +65,26 @@ func (s stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+type statusHTTPClient struct {
+	status int
+	body   string
+}
+
+func (s statusHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: s.status,
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
 type stubCache struct {
 	getBody []byte
 	getErr  error
 	addErr  error
+	mu      sync.Mutex
+	added   []string
 }
 
 func (s *stubCache) Get(key string) ([]byte, error) {
@@ -75,9 +95,18 @@ func (s *stubCache) Get(key string) ([]byte, error) {
 }
 
 func (s *stubCache) Add(key string, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.added = append(s.added, key)
 	return s.addErr
 }
 
+func (s *stubCache) List(prefix string) ([]domain.CacheEntry, error) { return nil, nil }
+
+func (s *stubCache) Purge(prefix string) (int, error) { return 0, nil }
+
+func (s *stubCache) Clear() error { return nil }
+
 func (s *stubCache) Done() {}
 
 const mewFixture = `{
@@ -177,7 +206,7 @@ func TestGetPokemon(t *testing.T) {
 				RandFloat64: func() float64 { return tc.rand },
 			}
 
-			err := getPokemon(&c, url)
+			err := getPokemon(context.Background(), &c, url)
 			if tc.wantErrAny {
 				if err == nil {
 					t.Fatal("expected non-nil error")
@@ -244,7 +273,7 @@ func TestGetPokemonCacheAddHandling(t *testing.T) {
 				RandFloat64: func() float64 { return 0.0 },
 			}
 
-			err := getPokemon(&c, url)
+			err := getPokemon(context.Background(), &c, url)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -277,6 +306,7 @@ func TestCommandCatch(t *testing.T) {
 			name:       "throwing pokeball write error",
 			args:       []string{"mew"},
 			outFactory: func() io.Writer { return errWriter{} },
+			client:     &stubHTTPClient{body: mewFixture},
 			wantErr:    writeError,
 		},
 		{
@@ -331,7 +361,7 @@ func TestCommandCatch(t *testing.T) {
 				RandFloat64: func() float64 { return tc.rand },
 			}
 
-			err := commandCatch(&c)
+			err := commandCatch(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -373,7 +403,7 @@ func TestGetPokemonUsesCacheWhenPresent(t *testing.T) {
 		RandFloat64: func() float64 { return 0.0 },
 	}
 
-	err = getPokemon(&c, url)
+	err = getPokemon(context.Background(), &c, url)
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
@@ -399,3 +429,236 @@ func TestNewCatchCommand(t *testing.T) {
 		t.Fatal("expected command callback not to be nil")
 	}
 }
+
+type savingPokedex struct {
+	*pokedex.Pokedex
+	saves   int
+	saveErr error
+}
+
+func (s *savingPokedex) Save() error {
+	s.saves++
+	return s.saveErr
+}
+
+func TestGetPokemonSavesPokedex(t *testing.T) {
+	t.Parallel()
+
+	url := "https://example.test/api/v2/pokemon/mew"
+	tests := []struct {
+		name         string
+		rand         float64
+		saveErr      error
+		wantSaves    int
+		wantContains string
+	}{
+		{name: "saved after catch", rand: 0.0, wantSaves: 1, wantContains: "mew was caught!"},
+		{name: "not saved after escape", rand: 0.99, wantSaves: 0, wantContains: "mew escaped!"},
+		{name: "save error is reported", rand: 0.0, saveErr: errors.New("disk full"), wantSaves: 1, wantContains: "could not be saved: disk full"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			p := &savingPokedex{Pokedex: pokedex.NewPokedex(), saveErr: tc.saveErr}
+			c := config.Config{
+				Pokedex:     p,
+				Cache:       &stubCache{getErr: errors.New("cache miss")},
+				Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:         out,
+				HTTPClient:  &stubHTTPClient{body: mewFixture},
+				RandFloat64: func() float64 { return tc.rand },
+			}
+
+			err := getPokemon(context.Background(), &c, url)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if p.saves != tc.wantSaves {
+				t.Fatalf("expected %d saves, got %d", tc.wantSaves, p.saves)
+			}
+			if !strings.Contains(out.String(), tc.wantContains) {
+				t.Fatalf("expected output to contain %q, got %q", tc.wantContains, out.String())
+			}
+		})
+	}
+}
+
+func TestCommandCatchReportsHTTPErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		wantContains string
+	}{
+		{name: "not found", status: http.StatusNotFound, wantContains: "misspelled: not found, check the spelling"},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantContains: "misspelled: PokeAPI is rate limiting us"},
+		{name: "server error", status: http.StatusBadGateway, wantContains: "misspelled: PokeAPI is having trouble"},
+		{name: "other status", status: http.StatusForbidden, wantContains: "misspelled: PokeAPI rejected the request"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			cache := &stubCache{getErr: errors.New("cache miss")}
+			c := config.Config{
+				PokemonURL:  internal.SecondURL,
+				Args:        []string{"misspelled"},
+				Pokedex:     pokedex.NewPokedex(),
+				Cache:       cache,
+				Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:         out,
+				HTTPClient:  statusHTTPClient{status: tc.status, body: "Not Found"},
+				RandFloat64: func() float64 { return 0.0 },
+			}
+
+			err := commandCatch(context.Background(), &c)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if !strings.Contains(out.String(), tc.wantContains) {
+				t.Fatalf("expected output to contain %q, got %q", tc.wantContains, out.String())
+			}
+			if len(cache.added) != 0 {
+				t.Fatalf("expected error response not to be cached, got %v", cache.added)
+			}
+		})
+	}
+}
+
+type ctxHTTPClient struct{}
+
+func (ctxHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestCommandCatchCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := config.Config{
+		PokemonURL:  internal.SecondURL,
+		Args:        []string{"mew", "pikachu"},
+		Pokedex:     pokedex.NewPokedex(),
+		Cache:       &stubCache{getErr: errors.New("cache miss")},
+		Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:         &bytes.Buffer{},
+		HTTPClient:  ctxHTTPClient{},
+		RandFloat64: func() float64 { return 0.0 },
+	}
+
+	err := commandCatch(ctx, &c)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCatchPokemonRecordsEncounter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		encounters   map[string]pokedex.Encounter
+		rand         float64
+		wantLocation string
+		wantLevel    int
+	}{
+		{name: "explored area", encounters: map[string]pokedex.Encounter{"mew": {Area: "faraway-island", MinLevel: 30, MaxLevel: 30}}, wantLocation: "faraway-island", wantLevel: 30},
+		{name: "level range", encounters: map[string]pokedex.Encounter{"mew": {Area: "faraway-island", MinLevel: 10, MaxLevel: 14}}, rand: 0.5, wantLocation: "faraway-island", wantLevel: 12},
+		{name: "other species explored", encounters: map[string]pokedex.Encounter{"pidgey": {Area: "route-1", MinLevel: 2, MaxLevel: 4}}, wantLevel: defaultMinLevel},
+		{name: "nothing explored", wantLevel: defaultMinLevel},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := pokedex.NewPokedex()
+			out := &bytes.Buffer{}
+			c := config.Config{
+				Pokedex:     p,
+				Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:         out,
+				RandFloat64: func() float64 { return tc.rand },
+				Encounters:  tc.encounters,
+			}
+			if err := catchPokemon(&c, "mew", []byte(`{"id":151,"name":"mew","base_experience":0}`)); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			caught, err := p.GetPokemonByName("mew")
+			if err != nil {
+				t.Fatalf("expected mew to be caught, got %v", err)
+			}
+			if caught.Location != tc.wantLocation || caught.Level != tc.wantLevel || caught.CaughtAt.IsZero() {
+				t.Fatalf("expected %q at level %d with a catch time, got %+v", tc.wantLocation, tc.wantLevel, caught)
+			}
+			want := fmt.Sprintf("mew was caught! (#1, level %d)", tc.wantLevel)
+			if !strings.Contains(out.String(), want) {
+				t.Fatalf("expected output to contain %q, got %q", want, out.String())
+			}
+		})
+	}
+}
+
+func TestCatchPokemonKeepsMovesItemAndSprites(t *testing.T) {
+	t.Parallel()
+
+	body := `{"id":25,"name":"pikachu","base_experience":0,
+		"moves":[{"move":{"name":"thunder-shock"},"version_group_details":[{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"red-blue"}}]}],
+		"held_items":[{"item":{"name":"oran-berry"},"version_details":[{"rarity":50,"version":{"name":"x"}}]},{"item":{"name":"light-ball"},"version_details":[{"rarity":5,"version":{"name":"x"}},{"rarity":10,"version":{"name":"y"}}]}],
+		"species":{"name":"pikachu","url":"https://pokeapi.co/api/v2/pokemon-species/25/"},
+		"sprites":{"front_default":"front.png","front_shiny":"shiny.png","back_default":"back.png","other":{"official-artwork":{"front_default":"art.png"}}}}`
+
+	tests := []struct {
+		name     string
+		rolls    []float64
+		wantItem string
+	}{
+		{name: "first item", rolls: []float64{0, 0, 0.4}, wantItem: "oran-berry"},
+		{name: "second item", rolls: []float64{0, 0, 0.6, 0.09}, wantItem: "light-ball"},
+		{name: "no item", rolls: []float64{0, 0, 0.6, 0.1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rolls := tc.rolls
+			p := pokedex.NewPokedex()
+			c := config.Config{
+				Pokedex: p,
+				Logger:  logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:     &bytes.Buffer{},
+				RandFloat64: func() float64 {
+					if len(rolls) == 0 {
+						t.Fatal("unexpected extra roll")
+					}
+					roll := rolls[0]
+					rolls = rolls[1:]
+					return roll
+				},
+			}
+			if err := catchPokemon(&c, "pikachu", []byte(body)); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			caught, err := p.GetPokemonByName("pikachu")
+			if err != nil {
+				t.Fatalf("expected pikachu to be caught, got %v", err)
+			}
+			if caught.HeldItem != tc.wantItem {
+				t.Fatalf("expected held item %q, got %q", tc.wantItem, caught.HeldItem)
+			}
+			if len(caught.Moves) != 1 || caught.Moves[0].VersionGroupDetails[0].VersionGroup.Name != "red-blue" {
+				t.Fatalf("expected the learnset to be kept, got %+v", caught.Moves)
+			}
+			if caught.Species.Url != "https://pokeapi.co/api/v2/pokemon-species/25/" {
+				t.Fatalf("expected the species reference to be kept, got %+v", caught.Species)
+			}
+			want := pokedex.Sprites{FrontDefault: "front.png", FrontShiny: "shiny.png", BackDefault: "back.png", OfficialArtwork: "art.png"}
+			if caught.Sprites != want {
+				t.Fatalf("expected sprites %+v, got %+v", want, caught.Sprites)
+			}
+		})
+	}
+}
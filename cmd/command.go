@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -10,7 +11,7 @@ import (
 type CliCommand struct {
 	name        string
 	description string
-	Callback    func(c *config.Config) error
+	Callback    func(ctx context.Context, c *config.Config) error
 }
 
 const helpTextBase = `
@@ -31,6 +32,14 @@ func NewCommands() map[string]*CliCommand {
 	commands["catch"] = newCatchCommand()
 	commands["inspect"] = newInspectCommand()
 	commands["pokedex"] = newPokedexCommand()
+	commands["cache"] = newCacheCommand()
+	commands["prefetch"] = newPrefetchCommand()
+	commands["compact"] = newCompactCommand()
+	commands["release"] = newReleaseCommand()
+	commands["lookup"] = newLookupCommand()
+	commands["evolution"] = newEvolutionCommand()
+	commands["evolve"] = newEvolveCommand()
+	commands["matchup"] = newMatchupCommand()
 
 	keys := make([]string, 0, len(commands))
 	for key := range commands {
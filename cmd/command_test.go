@@ -18,6 +18,14 @@ func TestNewCommands(t *testing.T) {
 		{name: "catch"},
 		{name: "inspect"},
 		{name: "pokedex"},
+		{name: "cache"},
+		{name: "prefetch"},
+		{name: "compact"},
+		{name: "release"},
+		{name: "lookup"},
+		{name: "evolution"},
+		{name: "evolve"},
+		{name: "matchup"},
 	}
 
 	if len(commands) != len(tests) {
@@ -62,6 +70,14 @@ func TestNewCommandsHelpTextStable(t *testing.T) {
 		{name: "catch"},
 		{name: "inspect"},
 		{name: "pokedex"},
+		{name: "cache"},
+		{name: "prefetch"},
+		{name: "compact"},
+		{name: "release"},
+		{name: "lookup"},
+		{name: "evolution"},
+		{name: "evolve"},
+		{name: "matchup"},
 	}
 
 	for _, tc := range tests {
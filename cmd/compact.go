@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+)
+
+func commandCompact(ctx context.Context, c *config.Config) error {
+	if c.Compactor == nil {
+		return writeLine(c, "nothing to compact, set POKEDEX_STORAGE=kv to use the embedded store")
+	}
+	before, after, err := c.Compactor.Compact()
+	if err != nil {
+		c.Logger.Error("Error compacting store", "error", err)
+		return writeLine(c, fmt.Sprintf("compaction failed: %v", err))
+	}
+	c.Logger.Info("Compacted store", "before", before, "after", after)
+	return writeLine(c, fmt.Sprintf("Compacted store from %s to %s", formatBytes(int(before)), formatBytes(int(after))))
+}
+
+func newCompactCommand() *CliCommand {
+	return &CliCommand{
+		name:        "compact",
+		description: "Reclaim space in the embedded store",
+		Callback:    commandCompact,
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+)
+
+type stubCompactor struct {
+	before, after int64
+	err           error
+}
+
+func (s stubCompactor) Compact() (int64, int64, error) {
+	return s.before, s.after, s.err
+}
+
+func TestCommandCompact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		compactor domain.Compactor
+		want      string
+	}{
+		{name: "no store", want: "nothing to compact"},
+		{name: "compacted", compactor: stubCompactor{before: 3 << 20, after: 512 << 10}, want: "Compacted store from 3.0 MiB to 512.0 KiB"},
+		{name: "failure", compactor: stubCompactor{err: errors.New("disk full")}, want: "compaction failed: disk full"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			c := config.Config{
+				Logger:    logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:       out,
+				Compactor: tc.compactor,
+			}
+			if err := commandCompact(context.Background(), &c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if !strings.Contains(out.String(), tc.want) {
+				t.Fatalf("expected output to contain %q, got %q", tc.want, out.String())
+			}
+		})
+	}
+}
@@ -1,11 +1,63 @@
 package cmd
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 var (
-	ErrStop               = errors.New("stop")
-	ErrNoPokemon          = errors.New("no pokemon")
-	ErrNoPokemonToInspect = errors.New("no pokemon to inspect")
-	ErrNoAreaToExplore    = errors.New("no command to explore")
-	ErrEmptyPokedex       = errors.New("no pokedex found")
+	ErrStop                = errors.New("stop")
+	ErrNoPokemon           = errors.New("no pokemon")
+	ErrNoPokemonToInspect  = errors.New("no pokemon to inspect")
+	ErrNoAreaToExplore     = errors.New("no command to explore")
+	ErrEmptyPokedex        = errors.New("no pokedex found")
+	ErrNotAvailableOffline = errors.New("not available offline")
+	ErrNotFound            = errors.New("not found")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrServer              = errors.New("server error")
+	ErrUnexpectedStatus    = errors.New("unexpected status")
 )
+
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	Kind       error
+}
+
+func newHTTPError(statusCode int, url string) *HTTPError {
+	kind := ErrUnexpectedStatus
+	switch {
+	case statusCode == http.StatusNotFound:
+		kind = ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case statusCode >= 500:
+		kind = ErrServer
+	}
+	return &HTTPError{StatusCode: statusCode, URL: url, Kind: kind}
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%v: %d %s: %s", e.Kind, e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Kind
+}
+
+func fetchErrorMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, ErrNotAvailableOffline):
+		return "not available offline", true
+	case errors.Is(err, ErrNotFound):
+		return "not found, check the spelling", true
+	case errors.Is(err, ErrRateLimited):
+		return "PokeAPI is rate limiting us, try again in a moment", true
+	case errors.Is(err, ErrServer):
+		return "PokeAPI is having trouble right now, try again later", true
+	case errors.Is(err, ErrUnexpectedStatus):
+		return "PokeAPI rejected the request", true
+	}
+	return "", false
+}
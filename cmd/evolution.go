@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+)
+
+const evolutionUsage = "usage: evolution <name|id>..."
+
+func commandEvolution(ctx context.Context, c *config.Config) error {
+	if len(c.Args) == 0 {
+		return writeLine(c, evolutionUsage)
+	}
+	results := fetchAll(ctx, c, c.Args, func(arg string) string { return c.SpeciesURL + arg })
+	var failures []fetchResult
+	for i, r := range results {
+		if i > 0 {
+			if err := writeLine(c, ""); err != nil {
+				return err
+			}
+		}
+		if r.err == nil {
+			r.err = printEvolution(ctx, c, r.body)
+		}
+		if r.err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.Logger.Error("Error getting evolution chain: ", "url", r.url, "error", r.err)
+			failures = append(failures, r)
+		}
+	}
+	return reportFailures(c, len(results), failures)
+}
+
+func printEvolution(ctx context.Context, c *config.Config, body []byte) error {
+	var species PokemonSpecies
+	if err := json.Unmarshal(body, &species); err != nil {
+		c.Logger.Error("Error parsing response: ", "error", err)
+		return err
+	}
+	chain, err := getEvolutionChain(ctx, c, species.EvolutionChain.Url)
+	if err != nil {
+		return err
+	}
+	lines := []string{chain.Chain.Species.Name}
+	lines = appendEvolutions(lines, chain.Chain, "")
+	for _, line := range lines {
+		if err = writeLine(c, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendEvolutions draws the species link evolves into as an ASCII tree,
+// one line per species with the way it evolves in brackets.
+func appendEvolutions(lines []string, link ChainLink, prefix string) []string {
+	for i, next := range link.EvolvesTo {
+		branch, indent := "|-- ", "|   "
+		if i == len(link.EvolvesTo)-1 {
+			branch, indent = "`-- ", "    "
+		}
+		line := prefix + branch + next.Species.Name
+		if how := describeEvolution(next.EvolutionDetails); how != "" {
+			line += " (" + how + ")"
+		}
+		lines = append(lines, line)
+		lines = appendEvolutions(lines, next, prefix+indent)
+	}
+	return lines
+}
+
+// describeEvolution joins the distinct ways to evolve. Games often differ,
+// e.g. a trade in one generation and an item in another.
+func describeEvolution(details []EvolutionDetail) string {
+	var ways []string
+	seen := make(map[string]bool)
+	for _, d := range details {
+		way := d.describe()
+		if !seen[way] {
+			seen[way] = true
+			ways = append(ways, way)
+		}
+	}
+	return strings.Join(ways, " or ")
+}
+
+func (d EvolutionDetail) describe() string {
+	return strings.Join(append([]string{d.trigger()}, d.conditions()...), " ")
+}
+
+func (d EvolutionDetail) trigger() string {
+	switch d.Trigger.Name {
+	case "level-up":
+		if d.MinLevel > 0 {
+			return fmt.Sprintf("level %d", d.MinLevel)
+		}
+		return "level up"
+	case "use-item":
+		if d.Item != nil {
+			return "use " + d.Item.Name
+		}
+		return "use item"
+	default:
+		return strings.ReplaceAll(d.Trigger.Name, "-", " ")
+	}
+}
+
+// conditions describes the requirements of d beyond its trigger.
+func (d EvolutionDetail) conditions() []string {
+	var parts []string
+	if d.Item != nil && d.Trigger.Name != "use-item" {
+		parts = append(parts, "with "+d.Item.Name)
+	}
+	if d.HeldItem != nil {
+		parts = append(parts, "holding "+d.HeldItem.Name)
+	}
+	if d.TradeSpecies != nil {
+		parts = append(parts, "for "+d.TradeSpecies.Name)
+	}
+	if d.KnownMove != nil {
+		parts = append(parts, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.MinHappiness > 0 {
+		parts = append(parts, fmt.Sprintf("with %d friendship", d.MinHappiness))
+	}
+	if d.MinAffection > 0 {
+		parts = append(parts, fmt.Sprintf("with %d affection", d.MinAffection))
+	}
+	if d.TimeOfDay != "" {
+		parts = append(parts, "at "+d.TimeOfDay)
+	}
+	if d.Location != nil {
+		parts = append(parts, "at "+d.Location.Name)
+	}
+	return parts
+}
+
+func newEvolutionCommand() *CliCommand {
+	return &CliCommand{
+		name:        "evolution",
+		description: "Show the evolution tree of a pokemon species and how each stage evolves",
+		Callback:    commandEvolution,
+	}
+}
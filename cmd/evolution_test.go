@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokecache"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+// speciesServer serves fixed bodies by path. "{{url}}" in a body is replaced
+// with the server URL so responses can link to each other.
+func speciesServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(strings.ReplaceAll(body, "{{url}}", ts.URL)))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func speciesConfig(t *testing.T, ts *httptest.Server, out *bytes.Buffer) *config.Config {
+	t.Helper()
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Done)
+	return &config.Config{
+		SpeciesURL: ts.URL + "/pokemon-species/",
+		Language:   "en",
+		Pokedex:    pokedex.NewPokedex(),
+		Cache:      cache,
+		Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:        out,
+		HTTPClient: ts.Client(),
+	}
+}
+
+var evolutionRoutes = map[string]string{
+	"/pokemon-species/bulbasaur": `{"name":"bulbasaur","evolution_chain":{"url":"{{url}}/evolution-chain/1/"}}`,
+	"/evolution-chain/1/": `{"id":1,"chain":{"species":{"name":"bulbasaur"},"evolution_details":[],"evolves_to":[
+		{"species":{"name":"ivysaur"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":16,"item":null}],"evolves_to":[
+			{"species":{"name":"venusaur"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":32}],"evolves_to":[]}]}]}}`,
+	"/pokemon-species/133": `{"name":"eevee","evolution_chain":{"url":"{{url}}/evolution-chain/67/"}}`,
+	"/evolution-chain/67/": `{"id":67,"chain":{"species":{"name":"eevee"},"evolves_to":[
+		{"species":{"name":"vaporeon"},"evolution_details":[{"trigger":{"name":"use-item"},"item":{"name":"water-stone"}}]},
+		{"species":{"name":"espeon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_happiness":160,"time_of_day":"day"}]},
+		{"species":{"name":"leafeon"},"evolution_details":[
+			{"trigger":{"name":"level-up"},"location":{"name":"eterna-forest"}},
+			{"trigger":{"name":"use-item"},"item":{"name":"leaf-stone"}},
+			{"trigger":{"name":"use-item"},"item":{"name":"leaf-stone"}}]},
+		{"species":{"name":"sylveon"},"evolution_details":[{"trigger":{"name":"level-up"},"known_move_type":{"name":"fairy"},"min_affection":2}]}]}}`,
+	"/pokemon-species/onix": `{"name":"onix","evolution_chain":{"url":"{{url}}/evolution-chain/41/"}}`,
+	"/evolution-chain/41/": `{"id":41,"chain":{"species":{"name":"onix"},"evolves_to":[
+		{"species":{"name":"steelix"},"evolution_details":[{"trigger":{"name":"trade"},"held_item":{"name":"metal-coat"}}]}]}}`,
+	"/pokemon-species/mew": `{"name":"mew","evolution_chain":{"url":"{{url}}/evolution-chain/78/"}}`,
+	"/evolution-chain/78/": `{"id":78,"chain":{"species":{"name":"mew"},"evolves_to":[]}}`,
+}
+
+func TestCommandEvolution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "usage", want: evolutionUsage + "\n"},
+		{name: "linear chain", args: []string{"bulbasaur"}, want: "bulbasaur\n`-- ivysaur (level 16)\n    `-- venusaur (level 32)\n"},
+		{name: "branches by id", args: []string{"133"}, want: "eevee\n" +
+			"|-- vaporeon (use water-stone)\n" +
+			"|-- espeon (level up with 160 friendship at day)\n" +
+			"|-- leafeon (level up at eterna-forest or use leaf-stone)\n" +
+			"`-- sylveon (level up knowing a fairy move with 2 affection)\n"},
+		{name: "trade", args: []string{"onix"}, want: "onix\n`-- steelix (trade holding metal-coat)\n"},
+		{name: "several species", args: []string{"mew", "onix"}, want: "mew\n\nonix\n`-- steelix (trade holding metal-coat)\n"},
+		{name: "unknown species", args: []string{"missingno"}, want: "Could not finish 1 of 1:\n  - missingno: not found, check the spelling\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			c := speciesConfig(t, speciesServer(t, evolutionRoutes), out)
+			c.Args = tc.args
+			if err := commandEvolution(context.Background(), c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if out.String() != tc.want {
+				t.Fatalf("expected\n%s\ngot\n%s", tc.want, out.String())
+			}
+		})
+	}
+}
+
+func TestFlavorText(t *testing.T) {
+	t.Parallel()
+
+	var species PokemonSpecies
+	body := `{"flavor_text_entries":[
+		{"flavor_text":"When several of\nthese POKéMON\fgather, their\nelectricity could\nbuild.","language":{"name":"en"}},
+		{"flavor_text":"Il arrive que des\nPIKACHU se rassemblent.","language":{"name":"fr"}},
+		{"flavor_text":"It keeps its tail\nraised to monitor\fits surroundings.","language":{"name":"en"}}]}`
+	if err := json.Unmarshal([]byte(body), &species); err != nil {
+		t.Fatalf("invalid species fixture: %v", err)
+	}
+
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{language: "en", want: "It keeps its tail raised to monitor its surroundings."},
+		{language: "fr", want: "Il arrive que des PIKACHU se rassemblent."},
+		{language: "ja"},
+	}
+	for _, tc := range tests {
+		if got := species.flavorText(tc.language); got != tc.want {
+			t.Fatalf("flavorText(%q): expected %q, got %q", tc.language, tc.want, got)
+		}
+	}
+}
+
+func TestInspectShowsFlavorText(t *testing.T) {
+	t.Parallel()
+
+	ts := speciesServer(t, map[string]string{
+		"/pokemon-species/25/": `{"name":"pikachu","flavor_text_entries":[
+			{"flavor_text":"It keeps its tail\nraised.","language":{"name":"en"}},
+			{"flavor_text":"Il garde sa queue\nlevée.","language":{"name":"fr"}}]}`,
+	})
+	tests := []struct {
+		name       string
+		speciesURL string
+		language   string
+		want       string
+	}{
+		{name: "english", speciesURL: "/pokemon-species/25/", language: "en", want: "Pokedex entry: It keeps its tail raised."},
+		{name: "configured language", speciesURL: "/pokemon-species/25/", language: "fr", want: "Pokedex entry: Il garde sa queue levée."},
+		{name: "no entry in language", speciesURL: "/pokemon-species/25/", language: "ja"},
+		{name: "species unavailable", speciesURL: "/pokemon-species/missing/", language: "en"},
+		{name: "caught before species was stored", language: "en"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			c := speciesConfig(t, ts, out)
+			c.Language = tc.language
+			p := pokedex.Pokemon{Name: "pikachu"}
+			if tc.speciesURL != "" {
+				p.Species.Url = ts.URL + tc.speciesURL
+			}
+			c.Pokedex.Add(p)
+			c.Args = []string{"pikachu"}
+			if err := commandInspect(context.Background(), c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if tc.want != "" && !strings.Contains(out.String(), tc.want) {
+				t.Fatalf("expected output to contain %q, got %q", tc.want, out.String())
+			}
+			if tc.want == "" && strings.Contains(out.String(), "Pokedex entry") {
+				t.Fatalf("expected no flavor text, got %q", out.String())
+			}
+			if !strings.Contains(out.String(), "Caught: 1") {
+				t.Fatalf("expected the rest of the view to be printed, got %q", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+const evolveUsage = "usage: evolve <name|#instance> [into-species] [--use <item>] [--trade]"
+
+// evolveAction is what the player does alongside the evolution: using an
+// item on the pokemon or trading it.
+type evolveAction struct {
+	item  string
+	trade bool
+}
+
+func commandEvolve(ctx context.Context, c *config.Config) error {
+	var args []string
+	var action evolveAction
+	for i := 0; i < len(c.Args); i++ {
+		switch c.Args[i] {
+		case "--trade":
+			action.trade = true
+		case "--use":
+			if i+1 >= len(c.Args) {
+				return writeLine(c, evolveUsage)
+			}
+			i++
+			action.item = c.Args[i]
+		default:
+			args = append(args, c.Args[i])
+		}
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return writeLine(c, evolveUsage)
+	}
+	p, err := findInstance(c, args[0])
+	if err != nil {
+		return writeLine(c, "you have not caught that pokemon")
+	}
+	target := ""
+	if len(args) == 2 {
+		target = args[1]
+	}
+
+	speciesURL := p.Species.Url
+	if speciesURL == "" {
+		speciesURL = c.SpeciesURL + p.Name
+	}
+	species, err := getSpecies(ctx, c, speciesURL)
+	if err != nil {
+		return loadFailed(ctx, c, "the evolution chain", err)
+	}
+	chain, err := getEvolutionChain(ctx, c, species.EvolutionChain.Url)
+	if err != nil {
+		return loadFailed(ctx, c, "the evolution chain", err)
+	}
+	link := chain.Chain.find(species.Name)
+	if link == nil || len(link.EvolvesTo) == 0 {
+		return writeLine(c, fmt.Sprintf("%s does not evolve", p.Name))
+	}
+
+	var ready []ChainLink
+	var waiting []string
+	consumeHeld := make(map[string]bool)
+	for _, next := range link.EvolvesTo {
+		if target != "" && next.Species.Name != target {
+			continue
+		}
+		missing, consumes := evolutionMissing(p, next.EvolutionDetails, action)
+		if len(missing) > 0 {
+			waiting = append(waiting, fmt.Sprintf("  - %s: needs %s", next.Species.Name, strings.Join(missing, " or ")))
+			continue
+		}
+		ready = append(ready, next)
+		consumeHeld[next.Species.Name] = consumes
+	}
+	switch {
+	case target != "" && len(ready) == 0 && len(waiting) == 0:
+		return writeLine(c, fmt.Sprintf("%s does not evolve into %s", p.Name, target))
+	case len(ready) == 0:
+		if err = writeLine(c, fmt.Sprintf("%s #%d can't evolve yet:", p.Name, p.InstanceID)); err != nil {
+			return err
+		}
+		for _, line := range waiting {
+			if err = writeLine(c, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case len(ready) > 1:
+		names := make([]string, 0, len(ready))
+		for _, next := range ready {
+			names = append(names, next.Species.Name)
+		}
+		return writeLine(c, fmt.Sprintf("%s #%d can evolve into %s, pick one with evolve #%d <species>",
+			p.Name, p.InstanceID, strings.Join(names, ", "), p.InstanceID))
+	}
+
+	into := ready[0].Species.Name
+	form, err := defaultForm(ctx, c, ready[0].Species)
+	if err != nil {
+		return loadFailed(ctx, c, into, err)
+	}
+	evolved := evolvedInstance(p, form, consumeHeld[into])
+	c.Pokedex.Add(evolved)
+	c.Logger.Info("Evolved pokemon", "instance", p.InstanceID, "from", p.Name, "to", evolved.Name)
+	if err = writeLine(c, fmt.Sprintf("%s #%d evolved into %s!", p.Name, p.InstanceID, evolved.Name)); err != nil {
+		return err
+	}
+	return saveWithWarning(c)
+}
+
+// defaultForm fetches the default pokemon of species. Its name can differ
+// from the species', e.g. aegislash-shield for aegislash.
+func defaultForm(ctx context.Context, c *config.Config, species NamedResource) (pokedex.Pokemon, error) {
+	speciesURL := species.Url
+	if speciesURL == "" {
+		speciesURL = c.SpeciesURL + species.Name
+	}
+	s, err := getSpecies(ctx, c, speciesURL)
+	if err != nil {
+		return pokedex.Pokemon{}, err
+	}
+	url := s.defaultPokemonURL()
+	if url == "" {
+		url = c.PokemonURL + species.Name
+	}
+	body, err := getBodyWithCache(ctx, c, url)
+	if err != nil {
+		return pokedex.Pokemon{}, err
+	}
+	var pokemonFromAPI PokemonFromAPI
+	if err = json.Unmarshal(body, &pokemonFromAPI); err != nil {
+		c.Logger.Error("Error parsing response: ", "url", url, "error", err)
+		return pokedex.Pokemon{}, err
+	}
+	return pokemonFromAPI.toPokemon(), nil
+}
+
+func findInstance(c *config.Config, arg string) (pokedex.Pokemon, error) {
+	if id, ok := pokedex.ParseInstanceID(arg); ok {
+		return c.Pokedex.GetInstance(id)
+	}
+	return c.Pokedex.GetPokemonByName(arg)
+}
+
+// evolvedInstance carries the instance data of p over to the evolved
+// species and appends the evolution to its history. A held item that
+// triggered the evolution is used up.
+func evolvedInstance(p, evolved pokedex.Pokemon, consumeHeld bool) pokedex.Pokemon {
+	evolved.InstanceID = p.InstanceID
+	evolved.CaughtAt = p.CaughtAt
+	evolved.Location = p.Location
+	evolved.Level = p.Level
+	evolved.HeldItem = p.HeldItem
+	if consumeHeld {
+		evolved.HeldItem = ""
+	}
+	evolved.History = append(append([]pokedex.Evolution(nil), p.History...), pokedex.Evolution{
+		From:  p.Name,
+		To:    evolved.Name,
+		Level: p.Level,
+		At:    time.Now(),
+	})
+	return evolved
+}
+
+// evolutionMissing returns what p lacks for every way in details, or nil
+// when one of them is met. consumesHeld reports whether the way that was
+// met needs a held item.
+func evolutionMissing(p pokedex.Pokemon, details []EvolutionDetail, action evolveAction) (missing []string, consumesHeld bool) {
+	for _, d := range details {
+		unmet := d.unmet(p, action)
+		if len(unmet) == 0 {
+			return nil, d.HeldItem != nil
+		}
+		missing = append(missing, strings.Join(unmet, " and "))
+	}
+	if len(details) == 0 {
+		missing = append(missing, "a trigger PokeAPI doesn't describe")
+	}
+	return missing, false
+}
+
+// unmet lists the requirements of d that p doesn't meet. Conditions this
+// CLI doesn't track, such as friendship or time of day, are never met.
+func (d EvolutionDetail) unmet(p pokedex.Pokemon, action evolveAction) []string {
+	var unmet []string
+	switch d.Trigger.Name {
+	case "level-up":
+		if d.MinLevel > 0 && p.Level < d.MinLevel {
+			unmet = append(unmet, fmt.Sprintf("level %d (is level %d)", d.MinLevel, p.Level))
+		}
+	case "use-item":
+		if d.Item == nil || action.item != d.Item.Name {
+			unmet = append(unmet, d.trigger()+" (--use)")
+		}
+	case "trade":
+		if !action.trade {
+			unmet = append(unmet, "trade (--trade)")
+		}
+	default:
+		unmet = append(unmet, d.trigger()+", which isn't supported")
+	}
+	if d.HeldItem != nil && p.HeldItem != d.HeldItem.Name {
+		unmet = append(unmet, "holding "+d.HeldItem.Name)
+	}
+	if d.KnownMove != nil && !knowsMove(p, d.KnownMove.Name) {
+		unmet = append(unmet, "knowing "+d.KnownMove.Name)
+	}
+	untracked := d
+	untracked.HeldItem = nil
+	untracked.KnownMove = nil
+	return append(unmet, untracked.conditions()...)
+}
+
+// knowsMove reports whether p would have learned move by levelling up to
+// its current level in any game.
+func knowsMove(p pokedex.Pokemon, move string) bool {
+	for _, m := range p.Moves {
+		if m.Move.Name != move {
+			continue
+		}
+		for _, d := range m.VersionGroupDetails {
+			if d.MoveLearnMethod.Name == "level-up" && d.LevelLearnedAt <= p.Level {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newEvolveCommand() *CliCommand {
+	return &CliCommand{
+		name:        "evolve",
+		description: "Evolve a caught pokemon, by name or instance number (e.g. #3), when it meets its evolution requirements (--use <item>, --trade)",
+		Callback:    commandEvolve,
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+var evolveRoutes = map[string]string{
+	"/pokemon-species/charmander": `{"name":"charmander","evolution_chain":{"url":"{{url}}/evolution-chain/2/"}}`,
+	"/pokemon-species/charmeleon": `{"name":"charmeleon","evolution_chain":{"url":"{{url}}/evolution-chain/2/"}}`,
+	"/evolution-chain/2/": `{"chain":{"species":{"name":"charmander"},"evolves_to":[
+		{"species":{"name":"charmeleon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":16}],"evolves_to":[
+			{"species":{"name":"charizard"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":36}]}]}]}}`,
+	"/pokemon-species/eevee": `{"name":"eevee","evolution_chain":{"url":"{{url}}/evolution-chain/67/"}}`,
+	"/evolution-chain/67/": `{"chain":{"species":{"name":"eevee"},"evolves_to":[
+		{"species":{"name":"vaporeon"},"evolution_details":[{"trigger":{"name":"use-item"},"item":{"name":"water-stone"}}]},
+		{"species":{"name":"umbreon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_happiness":160,"time_of_day":"night"}]}]}}`,
+	"/pokemon-species/wurmple": `{"name":"wurmple","evolution_chain":{"url":"{{url}}/evolution-chain/135/"}}`,
+	"/evolution-chain/135/": `{"chain":{"species":{"name":"wurmple"},"evolves_to":[
+		{"species":{"name":"silcoon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":7}]},
+		{"species":{"name":"cascoon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":7}]}]}}`,
+	"/pokemon-species/onix": `{"name":"onix","evolution_chain":{"url":"{{url}}/evolution-chain/41/"}}`,
+	"/evolution-chain/41/": `{"chain":{"species":{"name":"onix"},"evolves_to":[
+		{"species":{"name":"steelix"},"evolution_details":[{"trigger":{"name":"trade"},"held_item":{"name":"metal-coat"}}]}]}}`,
+	"/pokemon-species/mew":     `{"name":"mew","evolution_chain":{"url":"{{url}}/evolution-chain/78/"}}`,
+	"/evolution-chain/78/":     `{"chain":{"species":{"name":"mew"},"evolves_to":[]}}`,
+	"/pokemon-species/honedge": `{"name":"honedge","evolution_chain":{"url":"{{url}}/evolution-chain/361/"}}`,
+	"/evolution-chain/361/": `{"chain":{"species":{"name":"honedge"},"evolves_to":[
+		{"species":{"name":"doublade","url":"{{url}}/pokemon-species/doublade"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":35}],"evolves_to":[
+			{"species":{"name":"aegislash","url":"{{url}}/pokemon-species/aegislash"},"evolution_details":[{"trigger":{"name":"use-item"},"item":{"name":"dusk-stone"}}]}]}]}}`,
+	"/pokemon-species/doublade": `{"name":"doublade","evolution_chain":{"url":"{{url}}/evolution-chain/361/"},
+		"varieties":[{"is_default":true,"pokemon":{"name":"doublade","url":"{{url}}/pokemon/doublade"}}]}`,
+	"/pokemon/doublade": `{"name":`,
+	"/pokemon-species/aegislash": `{"name":"aegislash","evolution_chain":{"url":"{{url}}/evolution-chain/361/"},
+		"varieties":[{"is_default":false,"pokemon":{"name":"aegislash-blade","url":"{{url}}/pokemon/aegislash-blade"}},
+			{"is_default":true,"pokemon":{"name":"aegislash-shield","url":"{{url}}/pokemon/aegislash-shield"}}]}`,
+	"/pokemon/aegislash-shield": `{"name":"aegislash-shield","species":{"name":"aegislash","url":"{{url}}/pokemon-species/aegislash"}}`,
+}
+
+func init() {
+	for _, name := range []string{"charmeleon", "charizard", "vaporeon", "silcoon", "cascoon", "steelix"} {
+		evolveRoutes["/pokemon/"+name] = fmt.Sprintf(`{"name":%q,"species":{"name":%[1]q,"url":"{{url}}/pokemon-species/%[1]s"}}`, name)
+		if _, ok := evolveRoutes["/pokemon-species/"+name]; !ok {
+			evolveRoutes["/pokemon-species/"+name] = fmt.Sprintf(`{"name":%q,"varieties":[{"is_default":true,"pokemon":{"name":%[1]q,"url":"{{url}}/pokemon/%[1]s"}}]}`, name)
+		}
+	}
+}
+
+func TestCommandEvolve(t *testing.T) {
+	t.Parallel()
+
+	caughtAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		seed        pokedex.Pokemon
+		args        []string
+		want        []string
+		wantName    string
+		wantSpecies string
+		wantHeld    string
+		wantHistory []string
+	}{
+		{name: "usage", seed: pokedex.Pokemon{Name: "mew"}, want: []string{evolveUsage}, wantName: "mew"},
+		{name: "not caught", seed: pokedex.Pokemon{Name: "mew"}, args: []string{"#7"}, want: []string{"you have not caught that pokemon"}, wantName: "mew"},
+		{name: "bare number is not an instance", seed: pokedex.Pokemon{Name: "charmander", Level: 16}, args: []string{"1"}, want: []string{"you have not caught that pokemon"}, wantName: "charmander"},
+		{name: "level reached", seed: pokedex.Pokemon{Name: "charmander", Level: 16}, args: []string{"#1"}, want: []string{"charmander #1 evolved into charmeleon!"}, wantName: "charmeleon", wantHistory: []string{"charmander"}},
+		{name: "level too low", seed: pokedex.Pokemon{Name: "charmander", Level: 10}, args: []string{"charmander"}, want: []string{"charmander #1 can't evolve yet:", "  - charmeleon: needs level 16 (is level 10)"}, wantName: "charmander"},
+		{name: "keeps history", seed: pokedex.Pokemon{Name: "charmeleon", Level: 40, History: []pokedex.Evolution{{From: "charmander", To: "charmeleon", Level: 16}}}, args: []string{"#1"}, want: []string{"charmeleon #1 evolved into charizard!"}, wantName: "charizard", wantHistory: []string{"charmander", "charmeleon"}},
+		{name: "item used", seed: pokedex.Pokemon{Name: "eevee", Level: 5}, args: []string{"#1", "--use", "water-stone"}, want: []string{"eevee #1 evolved into vaporeon!"}, wantName: "vaporeon", wantHistory: []string{"eevee"}},
+		{name: "item missing", seed: pokedex.Pokemon{Name: "eevee", Level: 5}, args: []string{"#1"}, want: []string{"  - vaporeon: needs use water-stone (--use)", "  - umbreon: needs with 160 friendship and at night"}, wantName: "eevee"},
+		{name: "wrong target", seed: pokedex.Pokemon{Name: "eevee"}, args: []string{"#1", "charizard"}, want: []string{"eevee does not evolve into charizard"}, wantName: "eevee"},
+		{name: "several ready", seed: pokedex.Pokemon{Name: "wurmple", Level: 7}, args: []string{"#1"}, want: []string{"wurmple #1 can evolve into silcoon, cascoon, pick one with evolve #1 <species>"}, wantName: "wurmple"},
+		{name: "target picked", seed: pokedex.Pokemon{Name: "wurmple", Level: 7}, args: []string{"#1", "cascoon"}, want: []string{"wurmple #1 evolved into cascoon!"}, wantName: "cascoon", wantHistory: []string{"wurmple"}},
+		{name: "trade holding item", seed: pokedex.Pokemon{Name: "onix", HeldItem: "metal-coat"}, args: []string{"onix", "--trade"}, want: []string{"onix #1 evolved into steelix!"}, wantName: "steelix", wantHistory: []string{"onix"}},
+		{name: "trade without item", seed: pokedex.Pokemon{Name: "onix", HeldItem: "oran-berry"}, args: []string{"onix", "--trade"}, want: []string{"  - steelix: needs holding metal-coat"}, wantName: "onix", wantHeld: "oran-berry"},
+		{name: "does not evolve", seed: pokedex.Pokemon{Name: "mew"}, args: []string{"mew"}, want: []string{"mew does not evolve"}, wantName: "mew"},
+		{name: "default form", seed: pokedex.Pokemon{Name: "doublade"}, args: []string{"#1", "--use", "dusk-stone"}, want: []string{"doublade #1 evolved into aegislash-shield!"}, wantName: "aegislash-shield", wantSpecies: "aegislash", wantHistory: []string{"doublade"}},
+		{name: "bad pokemon response", seed: pokedex.Pokemon{Name: "honedge", Level: 35}, args: []string{"#1"}, want: []string{"could not load doublade: unexpected response from PokeAPI"}, wantName: "honedge"},
+		{name: "unknown species", seed: pokedex.Pokemon{Name: "missingno"}, args: []string{"#1"}, want: []string{"could not load the evolution chain: not found"}, wantName: "missingno"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			ts := speciesServer(t, evolveRoutes)
+			c := speciesConfig(t, ts, out)
+			c.PokemonURL = ts.URL + "/pokemon/"
+			p := &savingPokedex{Pokedex: pokedex.NewPokedex()}
+			c.Pokedex = p
+			seed := tc.seed
+			seed.CaughtAt = caughtAt
+			seed.Location = "route-1"
+			p.Add(seed)
+			c.Args = tc.args
+
+			if err := commandEvolve(context.Background(), c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("expected output to contain %q, got %q", want, out.String())
+				}
+			}
+			got, err := p.GetInstance(1)
+			if err != nil {
+				t.Fatalf("expected instance #1 to remain, got %v", err)
+			}
+			if got.Name != tc.wantName || got.HeldItem != tc.wantHeld {
+				t.Fatalf("expected %s holding %q, got %s holding %q", tc.wantName, tc.wantHeld, got.Name, got.HeldItem)
+			}
+			if got.Level != seed.Level || !got.CaughtAt.Equal(caughtAt) || got.Location != "route-1" {
+				t.Fatalf("expected instance data to be kept, got %+v", got)
+			}
+			var history []string
+			for _, e := range got.History {
+				history = append(history, e.From)
+			}
+			if strings.Join(history, ",") != strings.Join(tc.wantHistory, ",") {
+				t.Fatalf("expected history %v, got %v", tc.wantHistory, history)
+			}
+			wantSpecies := tc.wantSpecies
+			if wantSpecies == "" {
+				wantSpecies = tc.wantName
+			}
+			evolved := len(tc.wantHistory) > len(seed.History)
+			if evolved && (p.saves != 1 || got.History[len(got.History)-1].To != tc.wantName || got.Species.Name != wantSpecies) {
+				t.Fatalf("expected evolution to %s to be recorded and saved, got %+v (%d saves)", tc.wantName, got, p.saves)
+			}
+			if !evolved && p.saves != 0 {
+				t.Fatalf("expected no save without an evolution, got %d", p.saves)
+			}
+		})
+	}
+}
@@ -1,12 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
 )
 
-func commandExit(c *config.Config) error {
+func commandExit(ctx context.Context, c *config.Config) error {
 	_, err := fmt.Fprintln(c.Out, "Closing the Pokedex... Goodbye!")
 	if err != nil {
 		c.Logger.Error("unable to close the Pokedex", "error", err)
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
@@ -33,7 +34,7 @@ func TestCommandExit(t *testing.T) {
 				Out:    out,
 			}
 
-			err := commandExit(&c)
+			err := commandExit(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -76,7 +77,7 @@ func TestCommandHelp(t *testing.T) {
 				Out:    out,
 			}
 
-			err := commandHelp(&c)
+			err := commandHelp(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/Flarenzy/Pokedex/internal"
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
 )
 
 type PokemonInLocation struct {
+	Name                 string `json:"name"`
 	EncounterMethodRates []struct {
 		EncounterMethod struct {
 			Name string `json:"name"`
@@ -50,22 +53,27 @@ type PokemonInLocation struct {
 					Name string `json:"name"`
 					Url  string `json:"url"`
 				} `json:"method"`
-			}
-		}
+			} `json:"encounter_details"`
+		} `json:"version_details"`
 	} `json:"pokemon_encounters"`
 }
 
-func getPokemonInArea(c *config.Config, url string) error {
-	body, err := getBodyWithCache(c, url)
+func getPokemonInArea(ctx context.Context, c *config.Config, url string) error {
+	body, err := getBodyWithCache(ctx, c, url)
 	if err != nil {
 		return err
 	}
+	return printPokemonInArea(c, body)
+}
+
+func printPokemonInArea(c *config.Config, body []byte) error {
 	var pokemonInLocation PokemonInLocation
-	err = json.Unmarshal(body, &pokemonInLocation)
+	err := json.Unmarshal(body, &pokemonInLocation)
 	if err != nil {
 		c.Logger.Error("Error parsing response: ", "error", err)
 		return err
 	}
+	recordEncounters(c, pokemonInLocation)
 	for i, pokemon := range pokemonInLocation.PokemonEncounters {
 		_, err = fmt.Fprintf(c.Out, "Pokemon #%v: %v\n", i+1, pokemon.Pokemon.Name)
 		if err != nil {
@@ -73,22 +81,47 @@ func getPokemonInArea(c *config.Config, url string) error {
 			return err
 		}
 	}
-	c.Logger.Debug("Adding key to cache: ", "url", url)
 	return nil
 }
 
-func commandExplore(c *config.Config) error {
+// recordEncounters remembers where each species in area was seen and at which
+// levels so a following catch can fill in the location and roll a level.
+// When several explored areas have the same species the first one wins.
+func recordEncounters(c *config.Config, area PokemonInLocation) {
+	if c.Encounters == nil {
+		c.Encounters = make(map[string]pokedex.Encounter)
+	}
+	for _, pokemon := range area.PokemonEncounters {
+		if _, ok := c.Encounters[pokemon.Pokemon.Name]; ok {
+			continue
+		}
+		encounter := pokedex.Encounter{Area: area.Name}
+		for _, version := range pokemon.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				if encounter.MinLevel == 0 || detail.MinLevel < encounter.MinLevel {
+					encounter.MinLevel = detail.MinLevel
+				}
+				encounter.MaxLevel = max(encounter.MaxLevel, detail.MaxLevel)
+			}
+		}
+		c.Encounters[pokemon.Pokemon.Name] = encounter
+	}
+}
+
+func commandExplore(ctx context.Context, c *config.Config) error {
 	if len(c.Args) == 0 {
 		c.Logger.Info("No command to explore")
 		return ErrNoAreaToExplore
 	}
-	for _, arg := range c.Args {
-		baseURL := c.AreaURL
-		if baseURL == "" {
-			baseURL = internal.FirstURL
-		}
-		url := baseURL + arg
-		_, err := fmt.Fprintln(c.Out, "Exploring area: ", arg)
+	baseURL := c.AreaURL
+	if baseURL == "" {
+		baseURL = internal.FirstURL
+	}
+	results := fetchAll(ctx, c, c.Args, func(arg string) string { return baseURL + arg })
+	c.Encounters = make(map[string]pokedex.Encounter)
+	var failures []fetchResult
+	for _, r := range results {
+		_, err := fmt.Fprintln(c.Out, "Exploring area: ", r.arg)
 		if err != nil {
 			return err
 		}
@@ -96,9 +129,15 @@ func commandExplore(c *config.Config) error {
 		if err != nil {
 			return err
 		}
-		err = getPokemonInArea(c, url)
-		if err != nil {
-			c.Logger.Error("Error getting pokemon in area: ", "error", err)
+		if r.err == nil {
+			r.err = printPokemonInArea(c, r.body)
+		}
+		if r.err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.Logger.Error("Error getting pokemon in area: ", "url", r.url, "error", r.err)
+			failures = append(failures, r)
 		}
 		_, err = fmt.Fprintln(c.Out, "===================================")
 		if err != nil {
@@ -109,7 +148,7 @@ func commandExplore(c *config.Config) error {
 			return err
 		}
 	}
-	return nil
+	return reportFailures(c, len(results), failures)
 }
 
 func newExploreCommand() *CliCommand {
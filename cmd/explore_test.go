@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"log/slog"
+	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +15,7 @@ import (
 	"github.com/Flarenzy/Pokedex/internal/config"
 	"github.com/Flarenzy/Pokedex/internal/logging"
 	"github.com/Flarenzy/Pokedex/internal/pokecache"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
 )
 
 const exploreFixture = `{"pokemon_encounters":[{"pokemon":{"name":"pikachu","url":"u1"}},{"pokemon":{"name":"bulbasaur","url":"u2"}}]}`
@@ -24,6 +28,7 @@ func TestCommandExplore(t *testing.T) {
 		args         []string
 		body         string
 		clientErr    error
+		status       int
 		out          any
 		wantErr      error
 		wantContains []string
@@ -35,6 +40,7 @@ func TestCommandExplore(t *testing.T) {
 		{name: "bottom separator write error", args: []string{"forest"}, body: exploreFixture, out: &failOnWriteN{n: 5, err: writeError}, wantErr: writeError},
 		{name: "final newline write error", args: []string{"forest"}, body: exploreFixture, out: &failOnWriteN{n: 6, err: writeError}, wantErr: writeError},
 		{name: "pokemon fetch parse error is swallowed", args: []string{"forest"}, body: "{", out: &bytes.Buffer{}, wantContains: []string{"Exploring area:  forest", "==================================="}},
+		{name: "missing area is reported", args: []string{"nowhere"}, status: http.StatusNotFound, out: &bytes.Buffer{}, wantContains: []string{"Exploring area:  nowhere", "nowhere: not found, check the spelling"}},
 		{name: "pokemon fetch api error is swallowed", args: []string{"forest"}, clientErr: httpClientDoError, out: &bytes.Buffer{}, wantContains: []string{"Exploring area:  forest", "==================================="}},
 	}
 
@@ -55,8 +61,11 @@ func TestCommandExplore(t *testing.T) {
 				Out:        writer,
 				HTTPClient: &stubHTTPClient{body: tc.body, err: tc.clientErr},
 			}
+			if tc.status != 0 {
+				c.HTTPClient = statusHTTPClient{status: tc.status}
+			}
 
-			err := commandExplore(&c)
+			err := commandExplore(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -90,7 +99,7 @@ func TestGetPokemonInAreaUsesCache(t *testing.T) {
 		HTTPClient: &stubHTTPClient{err: httpClientDoError},
 	}
 
-	err := getPokemonInArea(&c, url)
+	err := getPokemonInArea(context.Background(), &c, url)
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
@@ -112,7 +121,7 @@ func TestGetPokemonInAreaWriteError(t *testing.T) {
 		HTTPClient: &stubHTTPClient{body: exploreFixture},
 	}
 
-	err := getPokemonInArea(&c, internal.FirstURL+"forest")
+	err := getPokemonInArea(context.Background(), &c, internal.FirstURL+"forest")
 	if !errors.Is(err, writeError) {
 		t.Fatalf("expected writeError, got %v", err)
 	}
@@ -126,3 +135,28 @@ func TestNewExploreCommand(t *testing.T) {
 		t.Fatal("invalid explore command")
 	}
 }
+
+func TestPrintPokemonInAreaRecordsEncounters(t *testing.T) {
+	t.Parallel()
+
+	body := `{"name":"route-1","pokemon_encounters":[
+		{"pokemon":{"name":"pidgey"},"version_details":[
+			{"encounter_details":[{"min_level":3,"max_level":4},{"min_level":2,"max_level":3}]},
+			{"encounter_details":[{"min_level":4,"max_level":7}]}]},
+		{"pokemon":{"name":"rattata"},"version_details":[]}]}`
+	c := config.Config{
+		Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:        &bytes.Buffer{},
+		Encounters: map[string]pokedex.Encounter{"rattata": {Area: "route-2", MinLevel: 5, MaxLevel: 5}},
+	}
+	if err := printPokemonInArea(&c, []byte(body)); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	want := map[string]pokedex.Encounter{
+		"pidgey":  {Area: "route-1", MinLevel: 2, MaxLevel: 7},
+		"rattata": {Area: "route-2", MinLevel: 5, MaxLevel: 5},
+	}
+	if !reflect.DeepEqual(c.Encounters, want) {
+		t.Fatalf("expected encounters %+v, got %+v", want, c.Encounters)
+	}
+}
@@ -1,29 +1,69 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
 	"github.com/Flarenzy/Pokedex/internal/pokecache"
 )
 
-func getBodyWithCache(c *config.Config, url string) ([]byte, error) {
+func getBodyWithCache(ctx context.Context, c *config.Config, url string) ([]byte, error) {
 	cachedBody, err := c.Cache.Get(url)
 	if err == nil {
 		c.Logger.Debug("Cache hit", "url", url)
 		return cachedBody, nil
 	}
+	if c.Offline {
+		return getOffline(c, url)
+	}
+	if c.Inflight == nil {
+		return fetchAndCache(ctx, c, url)
+	}
+
+	body, err, shared := c.Inflight.Do(url, func() ([]byte, error) {
+		return fetchAndCache(ctx, c, url)
+	})
+	if shared {
+		c.Logger.Debug("Coalesced request", "url", url)
+	}
+	return body, err
+}
 
-	body, err := getFromAPI(url, c)
+func fetchAndCache(ctx context.Context, c *config.Config, url string) ([]byte, error) {
+	revalidator, canRevalidate := c.Cache.(domain.Revalidator)
+	var stale []byte
+	var validators domain.Validators
+	if canRevalidate {
+		var err error
+		stale, validators, err = revalidator.GetStale(url)
+		if err != nil {
+			validators = domain.Validators{}
+		}
+	}
+
+	resp, err := fetchFromAPI(ctx, url, c, validators)
 	if err != nil {
 		return nil, err
 	}
+	if resp.notModified {
+		c.Logger.Debug("Revalidated cache entry", "url", url)
+		if err = revalidator.Refresh(url); err != nil {
+			return nil, err
+		}
+		return stale, nil
+	}
 
 	c.Logger.Debug("Adding key to cache: ", "url", url)
-	err = c.Cache.Add(url, body)
+	if canRevalidate {
+		err = revalidator.AddWithValidators(url, resp.body, resp.validators)
+	} else {
+		err = c.Cache.Add(url, resp.body)
+	}
 	if err != nil && !errors.Is(err, pokecache.ErrKeyExists) {
 		return nil, err
 	}
 
-	return body, nil
+	return resp.body, nil
 }
@@ -2,14 +2,24 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/synctest"
+	"time"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
 	"github.com/Flarenzy/Pokedex/internal/domain"
 	"github.com/Flarenzy/Pokedex/internal/logging"
 	"github.com/Flarenzy/Pokedex/internal/pokecache"
+	"github.com/Flarenzy/Pokedex/internal/singleflight"
 )
 
 func TestGetBodyWithCache(t *testing.T) {
@@ -81,7 +91,7 @@ func TestGetBodyWithCache(t *testing.T) {
 				HTTPClient: tc.httpClient,
 			}
 
-			got, err := getBodyWithCache(&c, url)
+			got, err := getBodyWithCache(context.Background(), &c, url)
 			if tc.wantErrAny {
 				if err == nil {
 					t.Fatal("expected non-nil error")
@@ -95,3 +105,142 @@ func TestGetBodyWithCache(t *testing.T) {
 		})
 	}
 }
+
+type blockingHTTPClient struct {
+	release chan struct{}
+	status  int
+	body    string
+	calls   atomic.Int32
+}
+
+func (b *blockingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	b.calls.Add(1)
+	<-b.release
+	return &http.Response{
+		StatusCode: b.status,
+		Body:       io.NopCloser(strings.NewReader(b.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestGetBodyWithCacheCoalescesConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	url := "https://example.test/api/v2/pokemon/mew"
+	tests := []struct {
+		name      string
+		status    int
+		wantErr   error
+		wantCache bool
+	}{
+		{name: "shared body", status: http.StatusOK, wantCache: true},
+		{name: "shared error", status: http.StatusNotFound, wantErr: ErrNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			synctest.Test(t, func(t *testing.T) {
+				cache := pokecache.NewCache(20 * time.Second)
+				t.Cleanup(cache.Done)
+				client := &blockingHTTPClient{release: make(chan struct{}), status: tc.status, body: mewFixture}
+				c := config.Config{
+					Cache:      cache,
+					Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+					Out:        &bytes.Buffer{},
+					HTTPClient: client,
+					Inflight:   &singleflight.Group[[]byte]{},
+				}
+
+				const callers = 8
+				bodies := make([][]byte, callers)
+				errs := make([]error, callers)
+				var wg sync.WaitGroup
+				for i := 0; i < callers; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						bodies[i], errs[i] = getBodyWithCache(context.Background(), &c, url)
+					}()
+				}
+				// Only release the request once every other caller is blocked
+				// on it, so none can arrive after it finished and fetch again.
+				synctest.Wait()
+				close(client.release)
+				wg.Wait()
+
+				if got := client.calls.Load(); got != 1 {
+					t.Fatalf("expected 1 request, got %d", got)
+				}
+				for i := 0; i < callers; i++ {
+					if !errors.Is(errs[i], tc.wantErr) {
+						t.Fatalf("caller %d: expected error %v, got %v", i, tc.wantErr, errs[i])
+					}
+					if tc.wantErr == nil && string(bodies[i]) != mewFixture {
+						t.Fatalf("caller %d: expected shared body, got %q", i, string(bodies[i]))
+					}
+				}
+				_, err := cache.Get(url)
+				if tc.wantCache && err != nil {
+					t.Fatalf("expected body to be cached, got %v", err)
+				}
+				if !tc.wantCache && err == nil {
+					t.Fatal("expected error response not to be cached")
+				}
+			})
+		})
+	}
+}
+
+func TestGetBodyWithCacheRevalidatesExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	var full, notModified atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		_, _ = w.Write([]byte(mewFixture))
+	}))
+	defer ts.Close()
+
+	cache := pokecache.NewCache(50 * time.Millisecond)
+	t.Cleanup(cache.Done)
+	c := config.Config{
+		Cache:      cache,
+		Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:        &bytes.Buffer{},
+		HTTPClient: ts.Client(),
+	}
+	url := ts.URL + "/api/v2/pokemon/mew"
+
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			time.Sleep(80 * time.Millisecond)
+		}
+		body, err := getBodyWithCache(context.Background(), &c, url)
+		if err != nil {
+			t.Fatalf("request %d: expected nil error, got %v", i, err)
+		}
+		if string(body) != mewFixture {
+			t.Fatalf("request %d: unexpected body %q", i, string(body))
+		}
+	}
+
+	if full.Load() != 1 || notModified.Load() != 1 {
+		t.Fatalf("expected 1 full download and 1 revalidation, got %d and %d", full.Load(), notModified.Load())
+	}
+	if stats := cache.Stats(); stats.Revalidations != 1 {
+		t.Fatalf("expected 1 revalidation in stats, got %+v", stats)
+	}
+	if _, err := cache.Get(url); err != nil {
+		t.Fatalf("expected revalidated entry to be fresh, got %v", err)
+	}
+}
@@ -1,12 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
 )
 
-func commandHelp(c *config.Config) error {
+func commandHelp(ctx context.Context, c *config.Config) error {
 	_, err := fmt.Fprintln(c.Out, helpText)
 	if err != nil {
 		c.Logger.Error("unable to write help message", "error", err.Error())
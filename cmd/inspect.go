@@ -1,20 +1,40 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
 	"github.com/Flarenzy/Pokedex/internal/pokedex"
 )
 
-func commandInspect(c *config.Config) error {
-	if len(c.Args) == 0 {
+func commandInspect(ctx context.Context, c *config.Config) error {
+	showMoves := false
+	var args []string
+	for _, arg := range c.Args {
+		if arg == "--moves" {
+			showMoves = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 0 {
 		c.Logger.Info("No pokemon to inspect")
 		return ErrNoPokemonToInspect
 	}
-	for _, arg := range c.Args {
-		p, err := c.Pokedex.GetPokemonByName(arg)
+	for _, arg := range args {
+		id, byInstance := pokedex.ParseInstanceID(arg)
+		var p pokedex.Pokemon
+		var err error
+		if byInstance {
+			p, err = c.Pokedex.GetInstance(id)
+		} else {
+			p, err = c.Pokedex.GetPokemonByName(arg)
+		}
 		if err != nil {
 			if errors.Is(err, pokedex.ErrPokemonNotFound) {
 				_, err = fmt.Fprintf(c.Out, "you have not caught that pokemon")
@@ -29,31 +49,196 @@ func commandInspect(c *config.Config) error {
 			return err
 		}
 		c.Logger.Debug("Printing info about pokemon: ", "name", p.Name)
-		_, err = fmt.Fprintf(c.Out, "Name: %v\nHeight: %v\nWeight: %v\n", p.Name, p.Height, p.Weight)
-		if err != nil {
+		if err = printPokemonDetails(c, p); err != nil {
 			return err
 		}
-		_, err = fmt.Fprintln(c.Out, "Stats:")
-		if err != nil {
+		if err = printFlavorText(ctx, c, p); err != nil {
 			return err
 		}
-		for _, stat := range p.Stats {
-			_, err = fmt.Fprintf(c.Out, "  -%v: %v\n", stat.Stat.Name, stat.BaseStat)
-			if err != nil {
-				c.Logger.Error("Error writing response: ", "error", err)
+		if showMoves {
+			if err = printMoves(c, p); err != nil {
 				return err
 			}
 		}
-		_, err = fmt.Fprintln(c.Out, "Types:")
-		if err != nil {
+		instances := []pokedex.Pokemon{p}
+		if !byInstance {
+			instances = c.Pokedex.GetInstances(p.Name)
+		}
+		if err = printInstances(c, instances); err != nil {
+			c.Logger.Error("Error writing response: ", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// statBarWidth is the length of a full stat bar, reached at maxBaseStat.
+const (
+	statBarWidth = 20
+	maxBaseStat  = 255
+)
+
+// printPokemonDetails writes the species data of p. PokeAPI reports height
+// in decimetres and weight in hectograms.
+func printPokemonDetails(c *config.Config, p pokedex.Pokemon) error {
+	_, err := fmt.Fprintf(c.Out, "Name: %v\nHeight: %s\nWeight: %s\nBase experience: %d\n",
+		p.Name, formatHeight(p.Height), formatWeight(p.Weight), p.BaseExperience)
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	if err = writeLine(c, "Abilities:"); err != nil {
+		return err
+	}
+	for _, a := range p.Abilities {
+		line := "  -" + a.Ability.Name
+		if a.IsHidden {
+			line += " (hidden)"
+		}
+		if err = writeLine(c, line); err != nil {
+			return err
+		}
+	}
+	if err = writeLine(c, "Stats:"); err != nil {
+		return err
+	}
+	total := 0
+	var evs []string
+	for _, stat := range p.Stats {
+		total += stat.BaseStat
+		if stat.Effort > 0 {
+			evs = append(evs, fmt.Sprintf("%d %s", stat.Effort, stat.Stat.Name))
+		}
+		if err = writeLine(c, fmt.Sprintf("  -%-16s %3d %s", stat.Stat.Name+":", stat.BaseStat, statBar(stat.BaseStat))); err != nil {
+			return err
+		}
+	}
+	if len(evs) == 0 {
+		evs = append(evs, "none")
+	}
+	_, err = fmt.Fprintf(c.Out, "Base stat total: %d\nEV yield: %s\n", total, strings.Join(evs, ", "))
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	if err = writeLine(c, "Types:"); err != nil {
+		return err
+	}
+	for _, t := range p.Types {
+		if err = writeLine(c, "  -"+t.Type.Name); err != nil {
+			return err
+		}
+	}
+	return printExtras(c, p)
+}
+
+// printExtras writes the species reference and sprites. Pokemon caught
+// before these were recorded have neither, so missing fields are skipped.
+func printExtras(c *config.Config, p pokedex.Pokemon) error {
+	if p.Species.Name != "" {
+		if err := writeLine(c, "Species: "+p.Species.Name); err != nil {
 			return err
 		}
-		for _, t := range p.Types {
-			_, err = fmt.Fprintf(c.Out, "  -%v\n", t.Type.Name)
-			if err != nil {
-				c.Logger.Error("Error writing response: ", "error", err)
+	}
+	sprites := []struct{ label, url string }{
+		{"front", p.Sprites.FrontDefault},
+		{"shiny", p.Sprites.FrontShiny},
+		{"back", p.Sprites.BackDefault},
+		{"artwork", p.Sprites.OfficialArtwork},
+	}
+	heading := false
+	for _, sprite := range sprites {
+		if sprite.url == "" {
+			continue
+		}
+		if !heading {
+			if err := writeLine(c, "Sprites:"); err != nil {
 				return err
 			}
+			heading = true
+		}
+		if err := writeLine(c, fmt.Sprintf("  -%s: %s", sprite.label, sprite.url)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printMoves writes the learnset, one line per move, folding version groups
+// that learn it the same way into one entry.
+func printMoves(c *config.Config, p pokedex.Pokemon) error {
+	if len(p.Moves) == 0 {
+		return writeLine(c, "Moves: none recorded")
+	}
+	if err := writeLine(c, fmt.Sprintf("Moves (%d):", len(p.Moves))); err != nil {
+		return err
+	}
+	for _, m := range p.Moves {
+		var methods []string
+		groups := make(map[string][]string)
+		for _, d := range m.VersionGroupDetails {
+			method := d.MoveLearnMethod.Name
+			if method == "level-up" {
+				method = fmt.Sprintf("level-up at %d", d.LevelLearnedAt)
+			}
+			if _, ok := groups[method]; !ok {
+				methods = append(methods, method)
+			}
+			groups[method] = append(groups[method], d.VersionGroup.Name)
+		}
+		ways := make([]string, 0, len(methods))
+		for _, method := range methods {
+			ways = append(ways, fmt.Sprintf("%s (%s)", method, strings.Join(groups[method], ", ")))
+		}
+		if err := writeLine(c, fmt.Sprintf("  -%s: %s", m.Move.Name, strings.Join(ways, ", "))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func statBar(value int) string {
+	filled := min(max((value*statBarWidth+maxBaseStat/2)/maxBaseStat, 0), statBarWidth)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", statBarWidth-filled) + "]"
+}
+
+func formatHeight(decimetres int) string {
+	inches := int(math.Round(float64(decimetres) * 3.937))
+	return fmt.Sprintf("%.1f m (%d'%d\")", float64(decimetres)/10, inches/12, inches%12)
+}
+
+func formatWeight(hectograms int) string {
+	return fmt.Sprintf("%.1f kg (%.1f lbs)", float64(hectograms)/10, float64(hectograms)*0.220462)
+}
+
+func printInstances(c *config.Config, instances []pokedex.Pokemon) error {
+	_, err := fmt.Fprintf(c.Out, "Caught: %d\n", len(instances))
+	if err != nil {
+		return err
+	}
+	for _, p := range instances {
+		location := p.Location
+		if location == "" {
+			location = "unknown location"
+		}
+		caughtAt := "at an unknown time"
+		if !p.CaughtAt.IsZero() {
+			caughtAt = p.CaughtAt.Local().Format(time.DateTime)
+		}
+		extra := ""
+		if p.HeldItem != "" {
+			extra += ", holding " + p.HeldItem
+		}
+		if len(p.History) > 0 {
+			evolvedFrom := make([]string, 0, len(p.History))
+			for _, e := range p.History {
+				evolvedFrom = append(evolvedFrom, e.From)
+			}
+			extra += ", evolved from " + strings.Join(evolvedFrom, ", ")
+		}
+		_, err = fmt.Fprintf(c.Out, "  #%d level %d, caught %s at %s%s\n", p.InstanceID, p.Level, caughtAt, location, extra)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -62,7 +247,7 @@ func commandInspect(c *config.Config) error {
 func newInspectCommand() *CliCommand {
 	return &CliCommand{
 		name:        "inspect",
-		description: `Inspect pokemon.`,
+		description: `Inspect pokemon by name or by instance number (e.g. #3), --moves lists the learnset.`,
 		Callback:    commandInspect,
 	}
 }
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
 	"github.com/Flarenzy/Pokedex/internal/domain"
@@ -19,7 +22,7 @@ type stubPokedex struct {
 	getErr error
 }
 
-func (s *stubPokedex) Add(p pokedex.Pokemon) {}
+func (s *stubPokedex) Add(p pokedex.Pokemon) pokedex.Pokemon { return p }
 
 func (s *stubPokedex) Remove(p pokedex.Pokemon) {}
 
@@ -34,6 +37,20 @@ func (s *stubPokedex) GetPokemonByName(name string) (pokedex.Pokemon, error) {
 	return s.byName, nil
 }
 
+func (s *stubPokedex) GetInstances(name string) []pokedex.Pokemon {
+	if s.getErr != nil {
+		return nil
+	}
+	return []pokedex.Pokemon{s.byName}
+}
+
+func (s *stubPokedex) GetInstance(id int) (pokedex.Pokemon, error) {
+	if s.getErr != nil {
+		return pokedex.Pokemon{}, s.getErr
+	}
+	return s.byName, nil
+}
+
 func pokemonWithStatsAndTypes() pokedex.Pokemon {
 	p := pokedex.Pokemon{Name: "mew", Height: 4, Weight: 40}
 	p.Stats = append(p.Stats, struct {
@@ -60,6 +77,13 @@ func pokemonWithStatsAndTypes() pokedex.Pokemon {
 	return p
 }
 
+func twoMews() domain.Pokedexer {
+	p := pokedex.NewPokedex()
+	p.Add(pokedex.Pokemon{Name: "mew", Level: 5, Location: "faraway-island", CaughtAt: time.Now()})
+	p.Add(pokedex.Pokemon{Name: "mew", Level: 9, HeldItem: "leftovers"})
+	return p
+}
+
 func TestCommandInspect(t *testing.T) {
 	t.Parallel()
 
@@ -77,12 +101,22 @@ func TestCommandInspect(t *testing.T) {
 		{name: "pokemon not found", args: []string{"mew"}, pokedex: pokedex.NewPokedex(), out: &bytes.Buffer{}, wantContains: []string{"you have not caught that pokemon"}},
 		{name: "not found write error", args: []string{"mew"}, pokedex: pokedex.NewPokedex(), out: errWriter{}, wantErr: writeError},
 		{name: "unexpected pokedex error", args: []string{"mew"}, pokedex: &stubPokedex{getErr: unknownErr}, out: &bytes.Buffer{}, wantErr: unknownErr},
-		{name: "success", args: []string{"mew"}, pokedex: func() domain.Pokedexer { p := pokedex.NewPokedex(); p.Add(seedPokemon); return p }(), out: &bytes.Buffer{}, wantContains: []string{"Name: mew", "Height: 4", "Weight: 40", "Stats:", "Types:"}},
+		{name: "success", args: []string{"mew"}, pokedex: func() domain.Pokedexer { p := pokedex.NewPokedex(); p.Add(seedPokemon); return p }(), out: &bytes.Buffer{}, wantContains: []string{"Name: mew", "Height: 0.4 m (1'4\")", "Weight: 4.0 kg (8.8 lbs)", "Stats:", "Types:"}},
 		{name: "details header write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: seedPokemon}, out: errWriter{}, wantErr: writeError},
-		{name: "stats heading write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: seedPokemon}, out: &failOnWriteN{n: 2, err: writeError}, wantErr: writeError},
-		{name: "stat row write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 3, err: writeError}, wantErr: writeError},
-		{name: "types heading write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 4, err: writeError}, wantErr: writeError},
-		{name: "type row write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 5, err: writeError}, wantErr: writeError},
+		{name: "abilities heading write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: seedPokemon}, out: &failOnWriteN{n: 2, err: writeError}, wantErr: writeError},
+		{name: "stats heading write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: seedPokemon}, out: &failOnWriteN{n: 3, err: writeError}, wantErr: writeError},
+		{name: "stat row write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 4, err: writeError}, wantErr: writeError},
+		{name: "stat total write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 5, err: writeError}, wantErr: writeError},
+		{name: "types heading write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 8, err: writeError}, wantErr: writeError},
+		{name: "only flags", args: []string{"--moves"}, pokedex: twoMews(), out: &bytes.Buffer{}, wantErr: ErrNoPokemonToInspect},
+		{name: "moves", args: []string{"--moves", "mew"}, pokedex: twoMews(), out: &bytes.Buffer{}, wantContains: []string{"Moves: none recorded", "Caught: 2"}},
+		{name: "type row write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 7, err: writeError}, wantErr: writeError},
+		{name: "instances", args: []string{"mew"}, pokedex: twoMews(), out: &bytes.Buffer{}, wantContains: []string{"Caught: 2", "  #1 level 5, caught ", " at faraway-island", "  #2 level 9, caught at an unknown time at unknown location, holding leftovers"}},
+		{name: "evolved", args: []string{"#1"}, pokedex: &stubPokedex{byName: pokedex.Pokemon{Name: "charizard", InstanceID: 1, Level: 36, History: []pokedex.Evolution{{From: "charmander"}, {From: "charmeleon"}}}}, out: &bytes.Buffer{}, wantContains: []string{"  #1 level 36, caught at an unknown time at unknown location, evolved from charmander, charmeleon"}},
+		{name: "by instance", args: []string{"#2"}, pokedex: twoMews(), out: &bytes.Buffer{}, wantContains: []string{"Name: mew", "Caught: 1", "  #2 level 9"}},
+		{name: "instance not found", args: []string{"#7"}, pokedex: twoMews(), out: &bytes.Buffer{}, wantContains: []string{"you have not caught that pokemon"}},
+		{name: "bare number is not an instance", args: []string{"2"}, pokedex: twoMews(), out: &bytes.Buffer{}, wantContains: []string{"you have not caught that pokemon"}},
+		{name: "instances write error", args: []string{"mew"}, pokedex: &stubPokedex{byName: pokemonWithStatsAndTypes()}, out: &failOnWriteN{n: 8, err: writeError}, wantErr: writeError},
 	}
 
 	for _, tc := range tests {
@@ -93,7 +127,7 @@ func TestCommandInspect(t *testing.T) {
 			}
 			c := config.Config{Args: tc.args, Pokedex: tc.pokedex, Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}), Out: writer}
 
-			err := commandInspect(&c)
+			err := commandInspect(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -128,9 +162,10 @@ func TestCommandPokedex(t *testing.T) {
 		wantContains []string
 	}{
 		{name: "empty pokedex", out: &bytes.Buffer{}, wantErr: ErrEmptyPokedex},
-		{name: "success", seedPokedex: true, out: &bytes.Buffer{}, wantContains: []string{"Your Pokedex:", "  - mew"}},
+		{name: "success", seedPokedex: true, out: &bytes.Buffer{}, wantContains: []string{"Your Pokedex:", "  - mew x2 (#1, #3)", "  - pidgey x1 (#2)", "3 pokemon, 2 species"}},
 		{name: "write error", seedPokedex: true, out: errWriter{}, wantErr: writeError},
 		{name: "pokemon row write error", seedPokedex: true, out: &failOnWriteN{n: 2, err: writeError}, wantErr: writeError},
+		{name: "footer write error", seedPokedex: true, out: &failOnWriteN{n: 4, err: writeError}, wantErr: writeError},
 	}
 
 	for _, tc := range tests {
@@ -138,6 +173,8 @@ func TestCommandPokedex(t *testing.T) {
 			p := pokedex.NewPokedex()
 			if tc.seedPokedex {
 				p.Add(pokedex.Pokemon{Name: "mew"})
+				p.Add(pokedex.Pokemon{Name: "pidgey"})
+				p.Add(pokedex.Pokemon{Name: "mew"})
 			}
 			writer, ok := tc.out.(interface{ Write([]byte) (int, error) })
 			if !ok {
@@ -145,7 +182,7 @@ func TestCommandPokedex(t *testing.T) {
 			}
 			c := config.Config{Pokedex: p, Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}), Out: writer}
 
-			err := commandPokedex(&c)
+			err := commandPokedex(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -168,3 +205,155 @@ func TestNewPokedexCommand(t *testing.T) {
 		t.Fatal("invalid pokedex command")
 	}
 }
+
+// speciesPokemon builds a caught pokemon from a PokeAPI-shaped JSON body so
+// tests can set types and stats without spelling out the anonymous structs.
+func speciesPokemon(t *testing.T, body string) pokedex.Pokemon {
+	t.Helper()
+	var p pokedex.Pokemon
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatalf("invalid pokemon fixture: %v", err)
+	}
+	return p
+}
+
+func TestCommandPokedexQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		args     []string
+		pageSize int
+		answers  []string
+		want     []string
+		wantNot  []string
+	}{
+		{name: "name order", want: []string{"  - charmander x2 (#1, #4)", "  - pikachu x1 (#3)", "  - vulpix x1 (#2)", "4 pokemon, 3 species", "By type: fire 3, electric 1"}},
+		{name: "sort by id", args: []string{"--sort", "id"}, want: []string{"  - charmander", "  - pikachu", "  - vulpix"}},
+		{name: "sort by weight", args: []string{"--sort", "weight"}, want: []string{"  - pikachu", "  - charmander", "  - vulpix"}},
+		{name: "sort by caught-at", args: []string{"--sort", "caught-at"}, want: []string{"  - vulpix", "  - pikachu", "  - charmander"}},
+		{name: "filter by type", args: []string{"--type", "fire"}, want: []string{"  - charmander", "  - vulpix", "3 pokemon, 2 species", "By type: fire 3"}},
+		{name: "filter by stat", args: []string{"--min-stat", "attack=52"}, want: []string{"  - charmander", "  - pikachu", "3 pokemon, 2 species"}},
+		{name: "combined filters", args: []string{"--type", "fire", "--min-stat", "speed=66"}, want: []string{"  - vulpix x1 (#2)", "1 pokemon, 1 species"}},
+		{name: "no match", args: []string{"--type", "water"}, want: []string{"No pokemon match those filters"}},
+		{name: "bad sort", args: []string{"--sort", "color"}, want: []string{pokedexUsage}},
+		{name: "bad stat", args: []string{"--min-stat", "attack"}, want: []string{pokedexUsage}},
+		{name: "missing value", args: []string{"--type"}, want: []string{pokedexUsage}},
+		{name: "next page", pageSize: 2, answers: []string{""}, want: []string{"  - pikachu", "-- 1 more, press Enter to continue or q to stop --", "  - vulpix", "4 pokemon, 3 species"}},
+		{name: "stop paging", pageSize: 1, answers: []string{"q"}, want: []string{"  - charmander", "-- 2 more", "4 pokemon, 3 species"}, wantNot: []string{"  - pikachu"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := pokedex.NewPokedex()
+			now := time.Now()
+			charmander := speciesPokemon(t, `{"id":4,"name":"charmander","weight":85,"types":[{"type":{"name":"fire"}}],"stats":[{"base_stat":52,"stat":{"name":"attack"}},{"base_stat":65,"stat":{"name":"speed"}}]}`)
+			charmander.CaughtAt = now.Add(-time.Hour)
+			p.Add(charmander)
+			vulpix := speciesPokemon(t, `{"id":37,"name":"vulpix","weight":99,"types":[{"type":{"name":"fire"}}],"stats":[{"base_stat":41,"stat":{"name":"attack"}},{"base_stat":65,"stat":{"name":"speed"}}]}`)
+			vulpix.CaughtAt = now.Add(-3 * time.Hour)
+			vulpix.Stats[1].BaseStat = 66
+			p.Add(vulpix)
+			pikachu := speciesPokemon(t, `{"id":25,"name":"pikachu","weight":60,"types":[{"type":{"name":"electric"}}],"stats":[{"base_stat":55,"stat":{"name":"attack"}},{"base_stat":90,"stat":{"name":"speed"}}]}`)
+			pikachu.CaughtAt = now.Add(-2 * time.Hour)
+			p.Add(pikachu)
+			charmander.CaughtAt = now
+			p.Add(charmander)
+
+			out := &bytes.Buffer{}
+			c := config.Config{
+				Args:     tc.args,
+				Pokedex:  p,
+				Logger:   logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:      out,
+				PageSize: tc.pageSize,
+				Input:    &answerReader{answers: tc.answers},
+			}
+			if err := commandPokedex(context.Background(), &c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			got := out.String()
+			pos := 0
+			for _, want := range tc.want {
+				i := strings.Index(got[pos:], want)
+				if i < 0 {
+					t.Fatalf("expected %q in order in output, got %q", want, got)
+				}
+				pos += i + len(want)
+			}
+			for _, unwanted := range tc.wantNot {
+				if strings.Contains(got, unwanted) {
+					t.Fatalf("expected output not to contain %q, got %q", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintPokemonDetails(t *testing.T) {
+	t.Parallel()
+
+	p := speciesPokemon(t, `{"name":"bulbasaur","height":7,"weight":69,"base_experience":64,
+		"abilities":[{"ability":{"name":"overgrow"}},{"is_hidden":true,"ability":{"name":"chlorophyll"}}],
+		"stats":[{"base_stat":45,"stat":{"name":"hp"}},{"base_stat":65,"effort":1,"stat":{"name":"special-attack"}},{"base_stat":255,"effort":2,"stat":{"name":"speed"}}],
+		"types":[{"type":{"name":"grass"}},{"type":{"name":"poison"}}],
+		"species":{"name":"bulbasaur"},
+		"sprites":{"front_default":"https://img.test/1.png","official_artwork":"https://img.test/art/1.png"}}`)
+	out := &bytes.Buffer{}
+	c := config.Config{Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}), Out: out}
+	if err := printPokemonDetails(&c, p); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	want := `Name: bulbasaur
+Height: 0.7 m (2'4")
+Weight: 6.9 kg (15.2 lbs)
+Base experience: 64
+Abilities:
+  -overgrow
+  -chlorophyll (hidden)
+Stats:
+  -hp:               45 [####................]
+  -special-attack:   65 [#####...............]
+  -speed:           255 [####################]
+Base stat total: 365
+EV yield: 1 special-attack, 2 speed
+Types:
+  -grass
+  -poison
+Species: bulbasaur
+Sprites:
+  -front: https://img.test/1.png
+  -artwork: https://img.test/art/1.png
+`
+	if out.String() != want {
+		t.Fatalf("expected details\n%s\ngot\n%s", want, out.String())
+	}
+}
+
+func TestPrintMoves(t *testing.T) {
+	t.Parallel()
+
+	p := speciesPokemon(t, `{"name":"bulbasaur","moves":[
+		{"move":{"name":"tackle"},"version_group_details":[
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"red-blue"}},
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"yellow"}},
+			{"level_learned_at":0,"move_learn_method":{"name":"machine"},"version_group":{"name":"sword-shield"}}]},
+		{"move":{"name":"vine-whip"},"version_group_details":[
+			{"level_learned_at":13,"move_learn_method":{"name":"level-up"},"version_group":{"name":"red-blue"}},
+			{"level_learned_at":10,"move_learn_method":{"name":"level-up"},"version_group":{"name":"x-y"}}]}]}`)
+	out := &bytes.Buffer{}
+	c := config.Config{Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}), Out: out}
+	if err := printMoves(&c, p); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	want := `Moves (2):
+  -tackle: level-up at 1 (red-blue, yellow), machine (sword-shield)
+  -vine-whip: level-up at 13 (red-blue), level-up at 10 (x-y)
+`
+	if out.String() != want {
+		t.Fatalf("expected moves\n%s\ngot\n%s", want, out.String())
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+)
+
+const lookupUsage = "usage: lookup <name|id>... [--moves]"
+
+func commandLookup(ctx context.Context, c *config.Config) error {
+	showMoves := false
+	var args []string
+	for _, arg := range c.Args {
+		if arg == "--moves" {
+			showMoves = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 0 {
+		return writeLine(c, lookupUsage)
+	}
+	results := fetchAll(ctx, c, args, func(arg string) string { return c.PokemonURL + arg })
+	var failures []fetchResult
+	for i, r := range results {
+		if i > 0 {
+			if err := writeLine(c, ""); err != nil {
+				return err
+			}
+		}
+		if r.err == nil {
+			r.err = printLookup(ctx, c, r.body, showMoves)
+		}
+		if r.err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.Logger.Error("Error looking up pokemon: ", "url", r.url, "error", r.err)
+			failures = append(failures, r)
+		}
+	}
+	return reportFailures(c, len(results), failures)
+}
+
+// printLookup renders body with the inspect detail view and says whether
+// the pokemon is already in the Pokedex.
+func printLookup(ctx context.Context, c *config.Config, body []byte, showMoves bool) error {
+	var pokemonFromAPI PokemonFromAPI
+	if err := json.Unmarshal(body, &pokemonFromAPI); err != nil {
+		c.Logger.Error("Error parsing response: ", "error", err)
+		return err
+	}
+	p := pokemonFromAPI.toPokemon()
+	if err := printPokemonDetails(c, p); err != nil {
+		return err
+	}
+	if err := printFlavorText(ctx, c, p); err != nil {
+		return err
+	}
+	if showMoves {
+		if err := printMoves(c, p); err != nil {
+			return err
+		}
+	}
+	instances := c.Pokedex.GetInstances(p.Name)
+	if len(instances) == 0 {
+		return writeLine(c, "Caught: not yet")
+	}
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, fmt.Sprintf("#%d", instance.InstanceID))
+	}
+	return writeLine(c, fmt.Sprintf("Caught: %d (%s)", len(instances), strings.Join(ids, ", ")))
+}
+
+func newLookupCommand() *CliCommand {
+	return &CliCommand{
+		name:        "lookup",
+		description: "Show a pokemon's details by name or number without catching it, --moves lists the learnset",
+		Callback:    commandLookup,
+	}
+}
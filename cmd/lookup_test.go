@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+func TestCommandLookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		args     []string
+		client   domain.HTTPClient
+		caught   int
+		wantURLs []string
+		want     []string
+		wantNot  []string
+	}{
+		{name: "usage", want: []string{lookupUsage}},
+		{name: "by name", args: []string{"mew"}, client: &stubHTTPClient{body: mewFixture}, wantURLs: []string{"https://pokeapi.test/pokemon/mew"}, want: []string{"Name: mew", "Stats:", "Types:", "Caught: not yet"}, wantNot: []string{"Moves"}},
+		{name: "by id", args: []string{"151"}, client: &stubHTTPClient{body: mewFixture}, wantURLs: []string{"https://pokeapi.test/pokemon/151"}, want: []string{"Name: mew"}},
+		{name: "already caught", args: []string{"mew"}, client: &stubHTTPClient{body: mewFixture}, caught: 2, want: []string{"Caught: 2 (#1, #2)"}},
+		{name: "with moves", args: []string{"--moves", "mew"}, client: &stubHTTPClient{body: mewFixture}, want: []string{"Name: mew", "Moves"}},
+		{name: "not found", args: []string{"missingno"}, client: statusHTTPClient{status: http.StatusNotFound}, want: []string{"Could not finish 1 of 1:", "  - missingno: not found"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := pokedex.NewPokedex()
+			for i := 0; i < tc.caught; i++ {
+				p.Add(pokedex.Pokemon{Name: "mew"})
+			}
+			cache := &stubCache{getErr: errors.New("cache miss")}
+			out := &bytes.Buffer{}
+			c := config.Config{
+				Args:       tc.args,
+				PokemonURL: "https://pokeapi.test/pokemon/",
+				Pokedex:    p,
+				Cache:      cache,
+				Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:        out,
+				HTTPClient: tc.client,
+			}
+			if err := commandLookup(context.Background(), &c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if tc.wantURLs != nil && !reflect.DeepEqual(cache.added, tc.wantURLs) {
+				t.Fatalf("expected responses cached under %v, got %v", tc.wantURLs, cache.added)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("expected output to contain %q, got %q", want, out.String())
+				}
+			}
+			for _, unwanted := range tc.wantNot {
+				if strings.Contains(out.String(), unwanted) {
+					t.Fatalf("expected output not to contain %q, got %q", unwanted, out.String())
+				}
+			}
+			if got := len(p.GetAllPokemon()); got != tc.caught {
+				t.Fatalf("expected lookup not to catch anything, got %d pokemon", got)
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
 )
 
 type Location struct {
@@ -21,16 +23,38 @@ type LocationArea struct {
 	Results  []Location `json:"results"`
 }
 
-func getFromAPI(url string, c *config.Config) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+type apiResponse struct {
+	body        []byte
+	validators  domain.Validators
+	notModified bool
+}
+
+func getFromAPI(ctx context.Context, url string, c *config.Config) ([]byte, error) {
+	resp, err := fetchFromAPI(ctx, url, c, domain.Validators{})
 	if err != nil {
-		c.Logger.Error("Error creating request: ", "error", err)
 		return []byte{}, err
 	}
+	return resp.body, nil
+}
+
+// fetchFromAPI performs a GET, made conditional when validators from a
+// previous response are given. A 304 is reported as notModified with no body.
+func fetchFromAPI(ctx context.Context, url string, c *config.Config, v domain.Validators) (apiResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		c.Logger.Error("Error creating request: ", "error", err)
+		return apiResponse{}, err
+	}
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		c.Logger.Error("Error making request: ", "error", err)
-		return []byte{}, err
+		return apiResponse{}, err
 	}
 	defer func(Body io.ReadCloser) {
 		err = Body.Close()
@@ -39,16 +63,30 @@ func getFromAPI(url string, c *config.Config) ([]byte, error) {
 			panic("error closing body")
 		}
 	}(resp.Body)
+	if resp.StatusCode == http.StatusNotModified && !v.Empty() {
+		return apiResponse{validators: v, notModified: true}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		httpErr := newHTTPError(resp.StatusCode, url)
+		c.Logger.Error("Unexpected response status: ", "url", url, "status", resp.StatusCode)
+		return apiResponse{}, httpErr
+	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		c.Logger.Error("Error reading response: ", "url", url, "error", err)
-		return []byte{}, err
+		return apiResponse{}, err
 	}
-	return body, nil
+	return apiResponse{
+		body: body,
+		validators: domain.Validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}, nil
 }
 
-func getLocationArea(c *config.Config, url string) error {
-	body, err := getBodyWithCache(c, url)
+func getLocationArea(ctx context.Context, c *config.Config, url string) error {
+	body, err := getBodyWithCache(ctx, c, url)
 	if err != nil {
 		return err
 	}
@@ -73,16 +111,30 @@ func getLocationArea(c *config.Config, url string) error {
 	return nil
 }
 
-func commandMap(c *config.Config) error {
-	url := c.Next
-	err := getLocationArea(c, url)
+func reportLocationAreaError(c *config.Config, err error) error {
+	msg, ok := fetchErrorMessage(err)
+	if !ok {
+		return err
+	}
+	c.Logger.Error("Error getting location area: ", "error", err)
+	_, err = fmt.Fprintf(c.Out, "location areas: %s\n", msg)
 	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
 		return err
 	}
 	return nil
 }
 
-func commandMapb(c *config.Config) error {
+func commandMap(ctx context.Context, c *config.Config) error {
+	url := c.Next
+	err := getLocationArea(ctx, c, url)
+	if err != nil {
+		return reportLocationAreaError(c, err)
+	}
+	return nil
+}
+
+func commandMapb(ctx context.Context, c *config.Config) error {
 	url := c.Previous
 	if url == "" {
 		_, err := fmt.Fprintln(c.Out, "you're on the first page")
@@ -92,10 +144,10 @@ func commandMapb(c *config.Config) error {
 		}
 		return nil
 	}
-	err := getLocationArea(c, url)
+	err := getLocationArea(ctx, c, url)
 	if err != nil {
 		c.Logger.Error("Error getting location area: ", "error", err)
-		return err
+		return reportLocationAreaError(c, err)
 	}
 	return nil
 }
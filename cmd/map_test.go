@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"log/slog"
@@ -92,7 +93,7 @@ func TestCommandMap(t *testing.T) {
 				HTTPClient: &stubHTTPClient{body: tc.body, err: tc.clientErr},
 			}
 
-			err := commandMap(&c)
+			err := commandMap(context.Background(), &c)
 			if tc.wantErrAny {
 				if err == nil {
 					t.Fatal("expected non-nil error")
@@ -152,7 +153,7 @@ func TestCommandMapb(t *testing.T) {
 				HTTPClient: &stubHTTPClient{body: tc.body, err: tc.clientErr},
 			}
 
-			err := commandMapb(&c)
+			err := commandMapb(context.Background(), &c)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
 			}
@@ -172,7 +173,7 @@ func TestGetFromAPIReadError(t *testing.T) {
 		HTTPClient: bodyHTTPClient{body: readErrBody{}},
 	}
 
-	_, err := getFromAPI("https://example.test/location-area", &c)
+	_, err := getFromAPI(context.Background(), "https://example.test/location-area", &c)
 	if err == nil {
 		t.Fatal("expected read error")
 	}
@@ -199,7 +200,7 @@ func TestGetFromAPIPanicsOnCloseError(t *testing.T) {
 		}
 	}()
 
-	_, _ = getFromAPI("https://example.test/location-area", &c)
+	_, _ = getFromAPI(context.Background(), "https://example.test/location-area", &c)
 }
 
 func TestNewMapCommands(t *testing.T) {
@@ -214,3 +215,66 @@ func TestNewMapCommands(t *testing.T) {
 		t.Fatal("invalid mapb command")
 	}
 }
+
+func TestGetFromAPIStatusErrors(t *testing.T) {
+	t.Parallel()
+
+	url := "https://example.test/api/v2/pokemon/misspelled"
+	tests := []struct {
+		name     string
+		status   int
+		wantKind error
+	}{
+		{name: "not found", status: http.StatusNotFound, wantKind: ErrNotFound},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantKind: ErrRateLimited},
+		{name: "internal server error", status: http.StatusInternalServerError, wantKind: ErrServer},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, wantKind: ErrServer},
+		{name: "bad request", status: http.StatusBadRequest, wantKind: ErrUnexpectedStatus},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := config.Config{
+				Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:        &bytes.Buffer{},
+				HTTPClient: statusHTTPClient{status: tc.status, body: "error"},
+			}
+
+			_, err := getFromAPI(context.Background(), url, &c)
+			if !errors.Is(err, tc.wantKind) {
+				t.Fatalf("expected %v, got %v", tc.wantKind, err)
+			}
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected *HTTPError, got %T", err)
+			}
+			if httpErr.StatusCode != tc.status || httpErr.URL != url {
+				t.Fatalf("expected status %d and url %q, got %d and %q", tc.status, url, httpErr.StatusCode, httpErr.URL)
+			}
+		})
+	}
+}
+
+func TestCommandMapReportsHTTPErrors(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	c := config.Config{
+		Next:       "https://example.test/location-area",
+		Cache:      &stubCache{getErr: errors.New("cache miss")},
+		Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:        out,
+		HTTPClient: statusHTTPClient{status: http.StatusServiceUnavailable},
+	}
+
+	err := commandMap(context.Background(), &c)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "location areas: PokeAPI is having trouble") {
+		t.Fatalf("expected friendly message, got %q", out.String())
+	}
+	if c.Next != "https://example.test/location-area" {
+		t.Fatalf("expected next page to stay unchanged, got %q", c.Next)
+	}
+}
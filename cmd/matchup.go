@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+	"github.com/Flarenzy/Pokedex/internal/typechart"
+)
+
+const matchupUsage = "usage: matchup <attacker-type|name|id|#instance> <name|id|#instance>"
+
+// typeNames are the types that appear on pokemon. An attacker argument
+// that isn't one of them is looked up as a pokemon.
+var typeNames = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock", "bug", "ghost", "steel",
+	"fire", "water", "grass", "electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+type PokemonType struct {
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo   []NamedResource `json:"double_damage_to"`
+		HalfDamageTo     []NamedResource `json:"half_damage_to"`
+		NoDamageTo       []NamedResource `json:"no_damage_to"`
+		DoubleDamageFrom []NamedResource `json:"double_damage_from"`
+		HalfDamageFrom   []NamedResource `json:"half_damage_from"`
+		NoDamageFrom     []NamedResource `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+func (t PokemonType) relations() typechart.DamageRelations {
+	names := func(resources []NamedResource) []string {
+		out := make([]string, 0, len(resources))
+		for _, r := range resources {
+			out = append(out, r.Name)
+		}
+		return out
+	}
+	rel := t.DamageRelations
+	return typechart.DamageRelations{
+		DoubleDamageTo:   names(rel.DoubleDamageTo),
+		HalfDamageTo:     names(rel.HalfDamageTo),
+		NoDamageTo:       names(rel.NoDamageTo),
+		DoubleDamageFrom: names(rel.DoubleDamageFrom),
+		HalfDamageFrom:   names(rel.HalfDamageFrom),
+		NoDamageFrom:     names(rel.NoDamageFrom),
+	}
+}
+
+func commandMatchup(ctx context.Context, c *config.Config) error {
+	if len(c.Args) != 2 {
+		return writeLine(c, matchupUsage)
+	}
+	attackerName, attackTypes := c.Args[0], []string{c.Args[0]}
+	if !slices.Contains(typeNames, c.Args[0]) {
+		attacker, err := matchupPokemon(ctx, c, c.Args[0])
+		if err != nil {
+			return matchupFailed(ctx, c, c.Args[0], err)
+		}
+		attackTypes = pokemonTypes(attacker)
+		attackerName = fmt.Sprintf("%s (%s)", attacker.Name, strings.Join(attackTypes, "/"))
+	}
+	defender, err := matchupPokemon(ctx, c, c.Args[1])
+	if err != nil {
+		return matchupFailed(ctx, c, c.Args[1], err)
+	}
+	defendTypes := pokemonTypes(defender)
+	if err = loadTypes(ctx, c, defendTypes); err != nil {
+		return loadFailed(ctx, c, "type data", err)
+	}
+
+	err = writeLine(c, fmt.Sprintf("%s vs %s (%s):", attackerName, defender.Name, strings.Join(defendTypes, "/")))
+	if err != nil {
+		return err
+	}
+	for _, t := range attackTypes {
+		m := c.TypeChart.Multiplier(t, defendTypes...)
+		if err = writeLine(c, fmt.Sprintf("  %s: %s, %s", t, formatMultiplier(m), effectiveness(m))); err != nil {
+			return err
+		}
+	}
+	var weak, resist, immune []string
+	defending := c.TypeChart.Defending(defendTypes...)
+	attackers := make([]string, 0, len(defending))
+	for t := range defending {
+		attackers = append(attackers, t)
+	}
+	sort.Slice(attackers, func(i, j int) bool {
+		a, b := defending[attackers[i]], defending[attackers[j]]
+		if a != b {
+			return a > b
+		}
+		return attackers[i] < attackers[j]
+	})
+	for _, t := range attackers {
+		m := defending[t]
+		switch {
+		case m == 0:
+			immune = append(immune, t)
+		case m < 1:
+			resist = append(resist, t+" "+formatMultiplier(m))
+		default:
+			weak = append(weak, t+" "+formatMultiplier(m))
+		}
+	}
+	for _, line := range []struct {
+		label string
+		types []string
+	}{
+		{"Weaknesses", weak},
+		{"Resistances", resist},
+		{"Immunities", immune},
+	} {
+		list := "none"
+		if len(line.types) > 0 {
+			list = strings.Join(line.types, ", ")
+		}
+		if err = writeLine(c, fmt.Sprintf("%s: %s", line.label, list)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchupPokemon resolves #N to a caught instance. Anything else is a name
+// or Pokedex number: a caught pokemon of that name is used when there is
+// one, otherwise it comes from PokeAPI.
+func matchupPokemon(ctx context.Context, c *config.Config, arg string) (pokedex.Pokemon, error) {
+	if strings.HasPrefix(arg, "#") {
+		return findInstance(c, arg)
+	}
+	if p, err := c.Pokedex.GetPokemonByName(arg); err == nil {
+		return p, nil
+	}
+	body, err := getBodyWithCache(ctx, c, c.PokemonURL+arg)
+	if err != nil {
+		return pokedex.Pokemon{}, err
+	}
+	var pokemonFromAPI PokemonFromAPI
+	if err = json.Unmarshal(body, &pokemonFromAPI); err != nil {
+		c.Logger.Error("Error parsing response: ", "url", c.PokemonURL+arg, "error", err)
+		return pokedex.Pokemon{}, err
+	}
+	return pokemonFromAPI.toPokemon(), nil
+}
+
+func matchupFailed(ctx context.Context, c *config.Config, arg string, err error) error {
+	if errors.Is(err, pokedex.ErrPokemonNotFound) {
+		return writeLine(c, "you have not caught "+arg)
+	}
+	return loadFailed(ctx, c, arg, err)
+}
+
+func pokemonTypes(p pokedex.Pokemon) []string {
+	types := make([]string, 0, len(p.Types))
+	for _, t := range p.Types {
+		types = append(types, t.Type.Name)
+	}
+	return types
+}
+
+// loadTypes adds the damage relations of every type not yet in the chart.
+// The chart lives on the config, so each type is fetched once per session.
+func loadTypes(ctx context.Context, c *config.Config, types []string) error {
+	var missing []string
+	for _, t := range types {
+		if !c.TypeChart.Has(t) {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	for _, r := range fetchAll(ctx, c, missing, func(t string) string { return c.TypeURL + t }) {
+		if r.err != nil {
+			return r.err
+		}
+		var t PokemonType
+		if err := json.Unmarshal(r.body, &t); err != nil {
+			c.Logger.Error("Error parsing response: ", "url", r.url, "error", err)
+			return err
+		}
+		c.TypeChart.Add(r.arg, t.relations())
+	}
+	return nil
+}
+
+func formatMultiplier(m float64) string {
+	return strconv.FormatFloat(m, 'g', -1, 64) + "x"
+}
+
+func effectiveness(m float64) string {
+	switch {
+	case m == 0:
+		return "no effect"
+	case m < 1:
+		return "not very effective"
+	case m > 1:
+		return "super effective"
+	}
+	return "normal damage"
+}
+
+func newMatchupCommand() *CliCommand {
+	return &CliCommand{
+		name:        "matchup",
+		description: "Show how an attacking type or pokemon fares against a pokemon, with its weaknesses, resistances and immunities",
+		Callback:    commandMatchup,
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/typechart"
+)
+
+var matchupRoutes = map[string]string{
+	"/type/grass": `{"name":"grass","damage_relations":{
+		"double_damage_to":[{"name":"ground"},{"name":"rock"},{"name":"water"}],
+		"half_damage_to":[{"name":"flying"},{"name":"poison"},{"name":"bug"},{"name":"steel"},{"name":"fire"},{"name":"grass"},{"name":"dragon"}],
+		"no_damage_to":[],
+		"double_damage_from":[{"name":"flying"},{"name":"poison"},{"name":"bug"},{"name":"fire"},{"name":"ice"}],
+		"half_damage_from":[{"name":"ground"},{"name":"water"},{"name":"grass"},{"name":"electric"}],
+		"no_damage_from":[]}}`,
+	"/type/poison": `{"name":"poison","damage_relations":{
+		"double_damage_to":[{"name":"grass"},{"name":"fairy"}],
+		"half_damage_to":[{"name":"poison"},{"name":"ground"},{"name":"rock"},{"name":"ghost"}],
+		"no_damage_to":[{"name":"steel"}],
+		"double_damage_from":[{"name":"ground"},{"name":"psychic"}],
+		"half_damage_from":[{"name":"fighting"},{"name":"poison"},{"name":"bug"},{"name":"grass"},{"name":"fairy"}],
+		"no_damage_from":[]}}`,
+	"/type/ghost": `{"name":"ghost","damage_relations":{
+		"double_damage_from":[{"name":"ghost"},{"name":"dark"}],
+		"half_damage_from":[{"name":"poison"},{"name":"bug"}],
+		"no_damage_from":[{"name":"normal"},{"name":"fighting"}]}}`,
+	"/pokemon/bulbasaur":  `{"name":"bulbasaur","types":[{"slot":1,"type":{"name":"grass"}},{"slot":2,"type":{"name":"poison"}}]}`,
+	"/pokemon/charmander": `{"name":"charmander","types":[{"slot":1,"type":{"name":"fire"}}]}`,
+	"/pokemon/1":          `{"name":"bulbasaur","types":[{"slot":1,"type":{"name":"grass"}},{"slot":2,"type":{"name":"poison"}}]}`,
+	"/pokemon/pidgeot":    `{"name":"pidgeot","types":[{"slot":1,"type":{"name":"normal"}},{"slot":2,"type":{"name":"flying"}}]}`,
+}
+
+func TestCommandMatchup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "usage", args: []string{"fire"}, want: matchupUsage + "\n"},
+		{name: "type against dual type", args: []string{"fire", "bulbasaur"}, want: "fire vs bulbasaur (grass/poison):\n" +
+			"  fire: 2x, super effective\n" +
+			"Weaknesses: fire 2x, flying 2x, ice 2x, psychic 2x\n" +
+			"Resistances: electric 0.5x, fairy 0.5x, fighting 0.5x, water 0.5x, grass 0.25x\n" +
+			"Immunities: none\n"},
+		{name: "dual types cancel out", args: []string{"ground", "bulbasaur"}, want: "  ground: 1x, normal damage\n"},
+		{name: "pokemon attacker", args: []string{"pidgeot", "bulbasaur"}, want: "pidgeot (normal/flying) vs bulbasaur (grass/poison):\n" +
+			"  normal: 1x, normal damage\n" +
+			"  flying: 2x, super effective\n"},
+		{name: "caught defender", args: []string{"normal", "#1"}, want: "normal vs gengar (ghost/poison):\n" +
+			"  normal: 0x, no effect\n" +
+			"Weaknesses: dark 2x, ghost 2x, ground 2x, psychic 2x\n" +
+			"Resistances: fairy 0.5x, grass 0.5x, bug 0.25x, poison 0.25x\n" +
+			"Immunities: fighting, normal\n"},
+		{name: "number is a pokedex id", args: []string{"fire", "1"}, want: "fire vs bulbasaur (grass/poison):\n"},
+		{name: "caught by name", args: []string{"normal", "gengar"}, want: "normal vs gengar (ghost/poison):\n"},
+		{name: "instance not caught", args: []string{"fire", "#2"}, want: "you have not caught #2\n"},
+		{name: "unknown defender", args: []string{"fire", "missingno"}, want: "could not load missingno: not found, check the spelling\n"},
+		{name: "unknown attacker", args: []string{"shadow", "bulbasaur"}, want: "could not load shadow: not found, check the spelling\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			ts := speciesServer(t, matchupRoutes)
+			c := speciesConfig(t, ts, out)
+			c.PokemonURL = ts.URL + "/pokemon/"
+			c.TypeURL = ts.URL + "/type/"
+			c.TypeChart = typechart.New()
+			gengar := speciesPokemon(t, `{"name":"gengar","types":[{"type":{"name":"ghost"}},{"type":{"name":"poison"}}]}`)
+			c.Pokedex.Add(gengar)
+			c.Args = tc.args
+
+			if err := commandMatchup(context.Background(), c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if !strings.Contains(out.String(), tc.want) {
+				t.Fatalf("expected output to contain\n%s\ngot\n%s", tc.want, out.String())
+			}
+		})
+	}
+}
+
+func TestMatchupReusesTypeChart(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	ts := speciesServer(t, matchupRoutes)
+	c := speciesConfig(t, ts, out)
+	c.PokemonURL = ts.URL + "/pokemon/"
+	c.TypeURL = ts.URL + "/type/"
+	c.TypeChart = typechart.New()
+	c.Args = []string{"fire", "bulbasaur"}
+	if err := commandMatchup(context.Background(), c); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !c.TypeChart.Has("grass") || !c.TypeChart.Has("poison") {
+		t.Fatal("expected the defender's types to be added to the chart")
+	}
+
+	c.TypeURL = ts.URL + "/missing/"
+	c.Pokedex.Add(speciesPokemon(t, matchupRoutes["/pokemon/bulbasaur"]))
+	out.Reset()
+	if err := commandMatchup(context.Background(), c); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "  fire: 2x, super effective") {
+		t.Fatalf("expected the chart to be reused without fetching types again, got %q", out.String())
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/fixtures"
+)
+
+// getOffline serves url without the network: an expired cache entry is
+// better than nothing, and fixtures fill in what was never downloaded.
+func getOffline(c *config.Config, url string) ([]byte, error) {
+	if revalidator, ok := c.Cache.(domain.Revalidator); ok {
+		if body, _, err := revalidator.GetStale(url); err == nil {
+			c.Logger.Debug("Stale cache hit", "url", url)
+			return body, nil
+		}
+	}
+	if c.FixturesDir != "" {
+		body, err := fixtures.Dir(c.FixturesDir).Load(url)
+		if err == nil {
+			c.Logger.Debug("Fixture hit", "url", url)
+			return body, nil
+		}
+		if !errors.Is(err, fixtures.ErrNoFixture) {
+			c.Logger.Error("Error reading fixture: ", "url", url, "error", err)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotAvailableOffline, url)
+}
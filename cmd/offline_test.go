@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokecache"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+const fixturesDir = "../testdata/pokeapi"
+
+// staleCache has expired every entry but still holds stale.
+type staleCache struct {
+	*stubCache
+	stale []byte
+}
+
+func (s staleCache) GetStale(key string) ([]byte, domain.Validators, error) {
+	if s.stale == nil {
+		return nil, domain.Validators{}, pokecache.ErrKeyNotFound
+	}
+	return s.stale, domain.Validators{ETag: `"stale"`}, nil
+}
+
+func (s staleCache) Refresh(key string) error { return nil }
+
+func (s staleCache) AddWithValidators(key string, val []byte, v domain.Validators) error {
+	return s.Add(key, val)
+}
+
+func TestGetBodyWithCacheOffline(t *testing.T) {
+	t.Parallel()
+
+	cacheMissErr := errors.New("cache miss")
+	tests := []struct {
+		name        string
+		url         string
+		cache       domain.Cacher
+		fixturesDir string
+		wantErr     error
+		wantBody    string
+	}{
+		{name: "cache hit", url: "https://example.test/api/v2/pokemon/mew", cache: &stubCache{getBody: []byte(mewFixture)}, wantBody: mewFixture},
+		{name: "fixture hit", url: "https://example.test/api/v2/pokemon/mew", cache: &stubCache{getErr: cacheMissErr}, fixturesDir: fixturesDir, wantBody: `"name":"mew"`},
+		{name: "missing fixture", url: "https://example.test/api/v2/pokemon/pidgey", cache: &stubCache{getErr: cacheMissErr}, fixturesDir: fixturesDir, wantErr: ErrNotAvailableOffline},
+		{name: "stale cache entry", url: "https://example.test/api/v2/location-area/canalave-city-area", cache: staleCache{stubCache: &stubCache{getErr: cacheMissErr}, stale: []byte(`{"name":"canalave-city-area"}`)}, fixturesDir: fixturesDir, wantBody: "canalave-city-area"},
+		{name: "no stale entry", url: "https://example.test/api/v2/pokemon/pidgey", cache: staleCache{stubCache: &stubCache{getErr: cacheMissErr}}, wantErr: ErrNotAvailableOffline},
+		{name: "no fixtures dir", url: "https://example.test/api/v2/pokemon/mew", cache: &stubCache{getErr: cacheMissErr}, wantErr: ErrNotAvailableOffline},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := config.Config{
+				Cache:       tc.cache,
+				Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:         &bytes.Buffer{},
+				HTTPClient:  stubHTTPClient{err: errors.New("http should not be called")},
+				Offline:     true,
+				FixturesDir: tc.fixturesDir,
+			}
+
+			got, err := getBodyWithCache(context.Background(), &c, tc.url)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr == nil && !strings.Contains(string(got), tc.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tc.wantBody, string(got))
+			}
+		})
+	}
+}
+
+func TestCommandCatchOfflineMissingPokemon(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	c := config.Config{
+		PokemonURL:  "https://example.test/api/v2/pokemon/",
+		Args:        []string{"pidgey"},
+		Pokedex:     pokedex.NewPokedex(),
+		Cache:       &stubCache{getErr: errors.New("cache miss")},
+		Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:         out,
+		HTTPClient:  stubHTTPClient{err: errors.New("http should not be called")},
+		RandFloat64: func() float64 { return 0.0 },
+		Offline:     true,
+		FixturesDir: fixturesDir,
+	}
+
+	err := commandCatch(context.Background(), &c)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "pidgey: not available offline") {
+		t.Fatalf("expected offline message, got %q", out.String())
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+)
+
+const defaultMaxWorkers = 4
+
+type fetchResult struct {
+	arg  string
+	url  string
+	body []byte
+	err  error
+}
+
+// fetchAll fetches the URL for every argument with at most c.MaxWorkers
+// requests in flight. Results come back in argument order.
+func fetchAll(ctx context.Context, c *config.Config, args []string, urlFor func(arg string) string) []fetchResult {
+	results := make([]fetchResult, len(args))
+	workers := c.MaxWorkers
+	if workers < 1 {
+		workers = defaultMaxWorkers
+	}
+	workers = min(workers, len(args))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				url := urlFor(args[i])
+				body, err := getBodyWithCache(ctx, c, url)
+				results[i] = fetchResult{arg: args[i], url: url, body: body, err: err}
+			}
+		}()
+	}
+	for i := range args {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func describeError(err error) string {
+	if msg, ok := fetchErrorMessage(err); ok {
+		return msg
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return "unexpected response from PokeAPI"
+	}
+	return err.Error()
+}
+
+// loadFailed reports a failed fetch of what and lets the command carry on,
+// unless the command itself was cancelled.
+func loadFailed(ctx context.Context, c *config.Config, what string, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	c.Logger.Error("Error loading from PokeAPI", "what", what, "error", err)
+	return writeLine(c, fmt.Sprintf("could not load %s: %s", what, describeError(err)))
+}
+
+func reportFailures(c *config.Config, total int, failures []fetchResult) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(c.Out, "Could not finish %d of %d:\n", len(failures), total)
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
+	for _, f := range failures {
+		_, err = fmt.Fprintf(c.Out, "  - %v: %s\n", f.arg, describeError(f.err))
+		if err != nil {
+			c.Logger.Error("Error writing response: ", "error", err)
+			return err
+		}
+	}
+	return nil
+}
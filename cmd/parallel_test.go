@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+type concurrentHTTPClient struct {
+	mu        sync.Mutex
+	inFlight  int
+	maxFlight int
+	bodies    map[string]string
+	delays    map[string]time.Duration
+}
+
+func (s *concurrentHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxFlight = max(s.maxFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	name := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	time.Sleep(s.delays[name])
+	body, ok := s.bodies[name]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func pokemonFixture(name string) string {
+	return fmt.Sprintf(`{"id":1,"name":%q,"base_experience":0}`, name)
+}
+
+func TestCommandCatchConcurrent(t *testing.T) {
+	t.Parallel()
+
+	client := &concurrentHTTPClient{
+		bodies: map[string]string{
+			"pidgey":  pokemonFixture("pidgey"),
+			"rattata": pokemonFixture("rattata"),
+			"zubat":   pokemonFixture("zubat"),
+			"onix":    pokemonFixture("onix"),
+		},
+		delays: map[string]time.Duration{
+			"pidgey": 30 * time.Millisecond,
+			"zubat":  10 * time.Millisecond,
+		},
+	}
+	out := &bytes.Buffer{}
+	c := config.Config{
+		PokemonURL:  "https://example.test/api/v2/pokemon/",
+		Args:        []string{"pidgey", "missingno", "rattata", "zubat", "onix"},
+		Pokedex:     pokedex.NewPokedex(),
+		Cache:       &stubCache{getErr: errors.New("cache miss")},
+		Logger:      logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:         out,
+		HTTPClient:  client,
+		RandFloat64: func() float64 { return 0.0 },
+		MaxWorkers:  2,
+	}
+
+	err := commandCatch(context.Background(), &c)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if client.maxFlight > 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", client.maxFlight)
+	}
+
+	got := out.String()
+	order := []string{
+		"Throwing a Pokeball at pidgey...",
+		"pidgey was caught!",
+		"Throwing a Pokeball at missingno...",
+		"Throwing a Pokeball at rattata...",
+		"rattata was caught!",
+		"Throwing a Pokeball at zubat...",
+		"Throwing a Pokeball at onix...",
+		"Could not finish 1 of 5:",
+		"  - missingno: not found, check the spelling",
+	}
+	last := -1
+	for _, s := range order {
+		idx := strings.Index(got, s)
+		if idx < 0 {
+			t.Fatalf("expected output to contain %q, got %q", s, got)
+		}
+		if idx < last {
+			t.Fatalf("expected %q to appear in typed order, got %q", s, got)
+		}
+		last = idx
+	}
+	if len(c.Pokedex.GetAllPokemon()) != 4 {
+		t.Fatalf("expected 4 pokemon caught, got %d", len(c.Pokedex.GetAllPokemon()))
+	}
+}
+
+func TestCommandExploreSummarizesFailures(t *testing.T) {
+	t.Parallel()
+
+	client := &concurrentHTTPClient{
+		bodies: map[string]string{"forest": exploreFixture, "broken": "{"},
+	}
+	out := &bytes.Buffer{}
+	c := config.Config{
+		AreaURL:    "https://example.test/api/v2/location-area/",
+		Args:       []string{"broken", "forest", "nowhere"},
+		Cache:      &stubCache{getErr: errors.New("cache miss")},
+		Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:        out,
+		HTTPClient: client,
+	}
+
+	err := commandExplore(context.Background(), &c)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	for _, s := range []string{
+		"Pokemon #1: pikachu",
+		"Could not finish 2 of 3:\n  - broken: unexpected response from PokeAPI\n  - nowhere: not found, check the spelling\n",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Fatalf("expected output to contain %q, got %q", s, out.String())
+		}
+	}
+}
+
+func TestReportFailuresWriteError(t *testing.T) {
+	t.Parallel()
+
+	c := config.Config{
+		Logger: logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:    &failOnWriteN{n: 2, err: writeError},
+	}
+	err := reportFailures(&c, 1, []fetchResult{{arg: "mew", err: errors.New("boom")}})
+	if !errors.Is(err, writeError) {
+		t.Fatalf("expected writeError, got %v", err)
+	}
+}
@@ -1,35 +1,235 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
 )
 
-func commandPokedex(c *config.Config) error {
+const pokedexUsage = "usage: pokedex [--sort name|id|weight|height|base-exp|caught-at] [--type <type>] [--min-stat <stat>=<value>]"
+
+var errPokedexUsage = errors.New(pokedexUsage)
+
+// speciesGroup holds every caught instance of one Pokemon. Sorting and
+// filtering use the first instance for species data such as weight and the
+// earliest catch for caught-at.
+type speciesGroup struct {
+	name      string
+	instances []pokedex.Pokemon
+}
+
+func (s speciesGroup) first() pokedex.Pokemon {
+	return s.instances[0]
+}
+
+var speciesOrder = map[string]func(a, b pokedex.Pokemon) bool{
+	"name":      func(a, b pokedex.Pokemon) bool { return false },
+	"id":        func(a, b pokedex.Pokemon) bool { return a.Id < b.Id },
+	"weight":    func(a, b pokedex.Pokemon) bool { return a.Weight < b.Weight },
+	"height":    func(a, b pokedex.Pokemon) bool { return a.Height < b.Height },
+	"base-exp":  func(a, b pokedex.Pokemon) bool { return a.BaseExperience < b.BaseExperience },
+	"caught-at": func(a, b pokedex.Pokemon) bool { return a.CaughtAt.Before(b.CaughtAt) },
+}
+
+type pokedexQuery struct {
+	sortBy   string
+	types    []string
+	minStats map[string]int
+}
+
+func parsePokedexArgs(args []string) (pokedexQuery, error) {
+	q := pokedexQuery{sortBy: "name", minStats: make(map[string]int)}
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return q, errPokedexUsage
+		}
+		flag, value := args[i], args[i+1]
+		i++
+		switch flag {
+		case "--sort":
+			if _, ok := speciesOrder[value]; !ok {
+				return q, errPokedexUsage
+			}
+			q.sortBy = value
+		case "--type":
+			q.types = append(q.types, value)
+		case "--min-stat":
+			stat, minValue, ok := strings.Cut(value, "=")
+			n, err := strconv.Atoi(minValue)
+			if !ok || stat == "" || err != nil {
+				return q, errPokedexUsage
+			}
+			q.minStats[stat] = n
+		default:
+			return q, errPokedexUsage
+		}
+	}
+	return q, nil
+}
+
+func (q pokedexQuery) matches(p pokedex.Pokemon) bool {
+	for _, want := range q.types {
+		if !hasType(p, want) {
+			return false
+		}
+	}
+	for stat, minValue := range q.minStats {
+		if baseStat(p, stat) < minValue {
+			return false
+		}
+	}
+	return true
+}
+
+func hasType(p pokedex.Pokemon, name string) bool {
+	for _, t := range p.Types {
+		if t.Type.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func baseStat(p pokedex.Pokemon, name string) int {
+	for _, stat := range p.Stats {
+		if stat.Stat.Name == name {
+			return stat.BaseStat
+		}
+	}
+	return 0
+}
+
+// groupSpecies returns the species that match q in the requested order,
+// falling back to name order for ties.
+func groupSpecies(all []pokedex.Pokemon, q pokedexQuery) []speciesGroup {
+	byName := make(map[string]int)
+	var groups []speciesGroup
+	for _, p := range all {
+		if !q.matches(p) {
+			continue
+		}
+		i, ok := byName[p.Name]
+		if !ok {
+			i = len(groups)
+			byName[p.Name] = i
+			groups = append(groups, speciesGroup{name: p.Name})
+		}
+		groups[i].instances = append(groups[i].instances, p)
+	}
+	less := speciesOrder[q.sortBy]
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].first(), groups[j].first()
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
+
+func commandPokedex(ctx context.Context, c *config.Config) error {
 	allPokemon := c.Pokedex.GetAllPokemon()
 	if len(allPokemon) == 0 {
 		return ErrEmptyPokedex
 	}
-	_, err := fmt.Fprintln(c.Out, "Your Pokedex:")
+	q, err := parsePokedexArgs(c.Args)
+	if err != nil {
+		return writeLine(c, pokedexUsage)
+	}
+	groups := groupSpecies(allPokemon, q)
+	if len(groups) == 0 {
+		return writeLine(c, "No pokemon match those filters")
+	}
+	_, err = fmt.Fprintln(c.Out, "Your Pokedex:")
 	if err != nil {
 		c.Logger.Error("Failed to write to output", "error", err)
 		return err
 	}
-	for _, pokemon := range allPokemon {
-		_, err = fmt.Fprintf(c.Out, "  - %v\n", pokemon.Name)
+	for i, group := range groups {
+		if i > 0 && c.PageSize > 0 && i%c.PageSize == 0 {
+			more, err := nextPage(c, len(groups)-i)
+			if err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+		}
+		ids := make([]string, 0, len(group.instances))
+		for _, p := range group.instances {
+			ids = append(ids, fmt.Sprintf("#%d", p.InstanceID))
+		}
+		_, err = fmt.Fprintf(c.Out, "  - %v x%d (%s)\n", group.name, len(ids), strings.Join(ids, ", "))
 		if err != nil {
 			c.Logger.Error("Failed to write to output", "error", err)
 			return err
 		}
 	}
-	return nil
+	return pokedexFooter(c, groups)
+}
+
+// nextPage asks whether to keep listing. Without an input to read from every
+// page is printed.
+func nextPage(c *config.Config, remaining int) (bool, error) {
+	if c.Input == nil {
+		return true, nil
+	}
+	err := writeLine(c, fmt.Sprintf("-- %d more, press Enter to continue or q to stop --", remaining))
+	if err != nil {
+		return false, err
+	}
+	answer, err := c.Input.Readline()
+	if err != nil {
+		return false, nil
+	}
+	return strings.TrimSpace(strings.ToLower(answer)) != "q", nil
+}
+
+// pokedexFooter summarises every listed species, including ones on pages
+// that were skipped, with the number of caught pokemon of each type.
+func pokedexFooter(c *config.Config, groups []speciesGroup) error {
+	total := 0
+	byType := make(map[string]int)
+	for _, group := range groups {
+		total += len(group.instances)
+		for _, t := range group.first().Types {
+			byType[t.Type.Name] += len(group.instances)
+		}
+	}
+	err := writeLine(c, fmt.Sprintf("%d pokemon, %d species", total, len(groups)))
+	if err != nil || len(byType) == 0 {
+		return err
+	}
+	types := make([]string, 0, len(byType))
+	for name := range byType {
+		types = append(types, name)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if byType[types[i]] != byType[types[j]] {
+			return byType[types[i]] > byType[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	counts := make([]string, 0, len(types))
+	for _, name := range types {
+		counts = append(counts, fmt.Sprintf("%s %d", name, byType[name]))
+	}
+	return writeLine(c, "By type: "+strings.Join(counts, ", "))
 }
 
 func newPokedexCommand() *CliCommand {
 	return &CliCommand{
 		name:        "pokedex",
-		description: `Displays all pokemon in pokedex`,
+		description: `Displays all pokemon in pokedex (--sort, --type and --min-stat to narrow the list)`,
 		Callback:    commandPokedex,
 	}
 }
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Flarenzy/Pokedex/internal"
+	"github.com/Flarenzy/Pokedex/internal/atomicfile"
+	"github.com/Flarenzy/Pokedex/internal/config"
+)
+
+const prefetchCheckpointFile = "prefetch.json"
+
+// prefetchCheckpoint records the first location-area page that has not been
+// fully fetched yet, so an interrupted prefetch can pick up where it stopped.
+// Seen lists the Pokemon already fetched so a resumed run doesn't count them
+// as new again.
+type prefetchCheckpoint struct {
+	Start   string   `json:"start"`
+	Next    string   `json:"next"`
+	Pages   int      `json:"pages"`
+	Areas   int      `json:"areas"`
+	Pokemon int      `json:"pokemon"`
+	Seen    []string `json:"seen,omitempty"`
+}
+
+func checkpointPath(c *config.Config) string {
+	if c.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(c.DataDir, prefetchCheckpointFile)
+}
+
+func loadCheckpoint(path, start string) (prefetchCheckpoint, bool) {
+	if path == "" {
+		return prefetchCheckpoint{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return prefetchCheckpoint{}, false
+	}
+	var cp prefetchCheckpoint
+	if err = json.Unmarshal(data, &cp); err != nil || cp.Start != start || cp.Next == "" {
+		return prefetchCheckpoint{}, false
+	}
+	return cp, true
+}
+
+func saveCheckpoint(path string, cp prefetchCheckpoint) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data)
+}
+
+func removeCheckpoint(path string) error {
+	if path == "" {
+		return nil
+	}
+	err := os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// commandPrefetch walks every location-area page, fetches each area and then
+// every Pokemon encountered there. Areas and Pokemon are fetched by name so
+// they land under the same cache keys explore and catch use. Requests go
+// through the normal client, so they share its rate limit.
+func commandPrefetch(ctx context.Context, c *config.Config) error {
+	if c.Offline {
+		return writeLine(c, "prefetch is not available offline")
+	}
+	areaURL := c.AreaURL
+	if areaURL == "" {
+		areaURL = internal.FirstURL
+	}
+	pokemonURL := c.PokemonURL
+	if pokemonURL == "" {
+		pokemonURL = internal.SecondURL
+	}
+
+	path := checkpointPath(c)
+	cp := prefetchCheckpoint{Start: areaURL, Next: areaURL}
+	restart := len(c.Args) > 0 && c.Args[0] == "--restart"
+	if resumed, ok := loadCheckpoint(path, areaURL); ok && !restart {
+		cp = resumed
+		err := writeLine(c, fmt.Sprintf("Resuming prefetch at page %d", cp.Pages+1))
+		if err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range cp.Seen {
+		seen[name] = true
+	}
+	var failures []fetchResult
+	total := 0
+	for cp.Next != "" {
+		var page LocationArea
+		var requested, found int
+		var pageFailures []fetchResult
+		body, err := getBodyWithCache(ctx, c, cp.Next)
+		if err == nil {
+			err = json.Unmarshal(body, &page)
+		}
+		if err == nil {
+			requested, found, pageFailures, err = prefetchPage(ctx, c, page, areaURL, pokemonURL, seen)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				if werr := writeLine(c, "Prefetch stopped, run prefetch again to resume"); werr != nil {
+					return werr
+				}
+				return ctx.Err()
+			}
+			c.Logger.Error("Error prefetching location areas", "url", cp.Next, "error", err)
+			return writeLine(c, fmt.Sprintf("prefetch stopped at page %d: %s", cp.Pages+1, describeError(err)))
+		}
+
+		failures = append(failures, pageFailures...)
+		total += len(page.Results) + requested
+		cp.Pages++
+		cp.Areas += len(page.Results)
+		cp.Pokemon += found
+		cp.Next = page.Next
+		cp.Seen = slices.Sorted(maps.Keys(seen))
+		_, err = fmt.Fprintf(c.Out, "Page %d: %d areas, %d new pokemon (%d areas, %d pokemon so far)\n",
+			cp.Pages, len(page.Results), found, cp.Areas, cp.Pokemon)
+		if err != nil {
+			c.Logger.Error("Error writing response: ", "error", err)
+			return err
+		}
+		if err = saveCheckpoint(path, cp); err != nil {
+			c.Logger.Error("Error saving prefetch checkpoint", "path", path, "error", err)
+		}
+	}
+
+	if err := removeCheckpoint(path); err != nil {
+		c.Logger.Error("Error removing prefetch checkpoint", "path", path, "error", err)
+	}
+	err := writeLine(c, fmt.Sprintf("Prefetch complete: %d pages, %d areas, %d pokemon", cp.Pages, cp.Areas, cp.Pokemon))
+	if err != nil {
+		return err
+	}
+	return reportFailures(c, total, failures)
+}
+
+// prefetchPage fetches the areas on one page and the Pokemon they list that
+// haven't been seen yet. Only Pokemon that were fetched are marked as seen,
+// so a failed one is tried again later. It returns how many Pokemon were
+// requested and fetched and the requests that failed; the error is only set
+// if ctx was cancelled.
+func prefetchPage(ctx context.Context, c *config.Config, page LocationArea, areaURL, pokemonURL string, seen map[string]bool) (int, int, []fetchResult, error) {
+	names := make([]string, 0, len(page.Results))
+	for _, area := range page.Results {
+		names = append(names, area.Name)
+	}
+	var failures []fetchResult
+	var pokemon []string
+	queued := make(map[string]bool)
+	for _, r := range fetchAll(ctx, c, names, func(name string) string { return areaURL + name }) {
+		if ctx.Err() != nil {
+			return 0, 0, nil, ctx.Err()
+		}
+		var area PokemonInLocation
+		if r.err == nil {
+			r.err = json.Unmarshal(r.body, &area)
+		}
+		if r.err != nil {
+			failures = append(failures, r)
+			continue
+		}
+		for _, encounter := range area.PokemonEncounters {
+			name := encounter.Pokemon.Name
+			if !seen[name] && !queued[name] {
+				queued[name] = true
+				pokemon = append(pokemon, name)
+			}
+		}
+	}
+	fetched := 0
+	for _, r := range fetchAll(ctx, c, pokemon, func(name string) string { return pokemonURL + name }) {
+		if ctx.Err() != nil {
+			return 0, 0, nil, ctx.Err()
+		}
+		if r.err != nil {
+			failures = append(failures, r)
+			continue
+		}
+		seen[r.arg] = true
+		fetched++
+	}
+	return len(pokemon), fetched, failures, nil
+}
+
+func newPrefetchCommand() *CliCommand {
+	return &CliCommand{
+		name:        "prefetch",
+		description: "Download every location area and the pokemon found there into the cache (--restart ignores saved progress)",
+		Callback:    commandPrefetch,
+	}
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokecache"
+)
+
+var prefetchAreas = map[string][]string{
+	"canalave-city-area":  {"tentacool", "staryu"},
+	"eterna-city-area":    {"staryu", "psyduck"},
+	"pastoria-city-area":  {"psyduck", "wooper"},
+	"sunyshore-city-area": {"tentacool"},
+}
+
+// prefetchServer serves two location-area pages of two areas each. Requests
+// for paths in fail get a 500.
+func prefetchServer(t *testing.T, fail map[string]bool) (*httptest.Server, *sync.Map) {
+	t.Helper()
+	requested := &sync.Map{}
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.RequestURI()
+		requested.Store(key, true)
+		if fail[key] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		switch {
+		case key == "/location-area/":
+			page := LocationArea{Next: ts.URL + "/location-area/?offset=2", Results: []Location{{Name: "canalave-city-area"}, {Name: "eterna-city-area"}}}
+			_ = json.NewEncoder(w).Encode(page)
+		case key == "/location-area/?offset=2":
+			page := LocationArea{Results: []Location{{Name: "pastoria-city-area"}, {Name: "sunyshore-city-area"}}}
+			_ = json.NewEncoder(w).Encode(page)
+		case strings.HasPrefix(key, "/location-area/"):
+			var encounters []string
+			for _, name := range prefetchAreas[strings.TrimPrefix(key, "/location-area/")] {
+				encounters = append(encounters, fmt.Sprintf(`{"pokemon":{"name":%q}}`, name))
+			}
+			_, _ = fmt.Fprintf(w, `{"pokemon_encounters":[%s]}`, strings.Join(encounters, ","))
+		case strings.HasPrefix(key, "/pokemon/"):
+			_, _ = fmt.Fprintf(w, `{"name":%q}`, strings.TrimPrefix(key, "/pokemon/"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, requested
+}
+
+func TestCommandPrefetch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		args           []string
+		checkpoint     bool
+		fail           []string
+		offline        bool
+		want           []string
+		wantRequested  []string
+		wantSkipped    []string
+		wantCached     []string
+		wantCheckpoint string
+		wantSeen       []string
+	}{
+		{
+			name:          "full run",
+			want:          []string{"Page 1: 2 areas, 3 new pokemon", "Page 2: 2 areas, 1 new pokemon", "Prefetch complete: 2 pages, 4 areas, 4 pokemon"},
+			wantRequested: []string{"/location-area/", "/location-area/sunyshore-city-area", "/pokemon/wooper"},
+			wantCached:    []string{"/location-area/eterna-city-area", "/pokemon/tentacool", "/pokemon/staryu", "/pokemon/psyduck", "/pokemon/wooper"},
+		},
+		{
+			name:          "resumes from checkpoint",
+			checkpoint:    true,
+			want:          []string{"Resuming prefetch at page 2", "Page 2: 2 areas, 1 new pokemon", "Prefetch complete: 2 pages, 4 areas, 4 pokemon"},
+			wantRequested: []string{"/location-area/?offset=2", "/pokemon/wooper"},
+			wantSkipped:   []string{"/location-area/", "/location-area/canalave-city-area"},
+		},
+		{
+			name:          "restart ignores checkpoint",
+			args:          []string{"--restart"},
+			checkpoint:    true,
+			want:          []string{"Page 1: 2 areas", "Prefetch complete: 2 pages, 4 areas, 4 pokemon"},
+			wantRequested: []string{"/location-area/", "/location-area/canalave-city-area"},
+		},
+		{
+			name:           "page failure keeps checkpoint",
+			fail:           []string{"/location-area/?offset=2"},
+			want:           []string{"Page 1: 2 areas", "prefetch stopped at page 2: PokeAPI is having trouble right now"},
+			wantSkipped:    []string{"/location-area/pastoria-city-area"},
+			wantCheckpoint: "/location-area/?offset=2",
+			wantSeen:       []string{"psyduck", "staryu", "tentacool"},
+		},
+		{
+			name:           "failed pokemon is not checkpointed",
+			fail:           []string{"/pokemon/staryu", "/location-area/?offset=2"},
+			want:           []string{"Page 1: 2 areas, 2 new pokemon (2 areas, 2 pokemon so far)"},
+			wantCheckpoint: "/location-area/?offset=2",
+			wantSeen:       []string{"psyduck", "tentacool"},
+		},
+		{
+			name:          "failed pokemon is reported",
+			fail:          []string{"/pokemon/staryu"},
+			want:          []string{"Prefetch complete: 2 pages, 4 areas, 3 pokemon", "Could not finish 1 of 8:", "  - staryu: PokeAPI is having trouble right now"},
+			wantRequested: []string{"/pokemon/wooper"},
+		},
+		{
+			name:          "area failure is reported",
+			fail:          []string{"/location-area/eterna-city-area"},
+			want:          []string{"Prefetch complete: 2 pages, 4 areas, 4 pokemon", "Could not finish 1 of 8:", "  - eterna-city-area: PokeAPI is having trouble right now"},
+			wantRequested: []string{"/pokemon/wooper"},
+		},
+		{
+			name:        "offline",
+			offline:     true,
+			want:        []string{"prefetch is not available offline"},
+			wantSkipped: []string{"/location-area/"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fail := make(map[string]bool)
+			for _, path := range tc.fail {
+				fail[path] = true
+			}
+			ts, requested := prefetchServer(t, fail)
+			cache := pokecache.NewCache(time.Hour)
+			t.Cleanup(cache.Done)
+			out := &bytes.Buffer{}
+			c := config.Config{
+				AreaURL:    ts.URL + "/location-area/",
+				PokemonURL: ts.URL + "/pokemon/",
+				Args:       tc.args,
+				Cache:      cache,
+				Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+				Out:        out,
+				HTTPClient: ts.Client(),
+				DataDir:    t.TempDir(),
+				Offline:    tc.offline,
+			}
+			path := filepath.Join(c.DataDir, prefetchCheckpointFile)
+			if tc.checkpoint {
+				cp := prefetchCheckpoint{Start: c.AreaURL, Next: ts.URL + "/location-area/?offset=2", Pages: 1, Areas: 2, Pokemon: 3,
+					Seen: []string{"psyduck", "staryu", "tentacool"}}
+				if err := saveCheckpoint(path, cp); err != nil {
+					t.Fatalf("error saving checkpoint: %v", err)
+				}
+			}
+
+			if err := commandPrefetch(context.Background(), &c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("expected output to contain %q, got %q", want, out.String())
+				}
+			}
+			for _, key := range tc.wantRequested {
+				if _, ok := requested.Load(key); !ok {
+					t.Fatalf("expected %s to be requested", key)
+				}
+			}
+			for _, key := range tc.wantSkipped {
+				if _, ok := requested.Load(key); ok {
+					t.Fatalf("expected %s not to be requested", key)
+				}
+			}
+
+			cp, ok := loadCheckpoint(path, c.AreaURL)
+			if tc.wantCheckpoint == "" && ok {
+				t.Fatalf("expected checkpoint to be removed, got %+v", cp)
+			}
+			if tc.wantCheckpoint != "" && cp.Next != ts.URL+tc.wantCheckpoint {
+				t.Fatalf("expected checkpoint at %s, got %+v", tc.wantCheckpoint, cp)
+			}
+			if tc.wantCheckpoint != "" && !slices.Equal(cp.Seen, tc.wantSeen) {
+				t.Fatalf("expected %v in the checkpoint, got %v", tc.wantSeen, cp.Seen)
+			}
+			for _, key := range tc.wantCached {
+				if _, err := cache.Get(ts.URL + key); err != nil {
+					t.Fatalf("expected %s to be cached, got %v", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCommandPrefetchCancelled(t *testing.T) {
+	t.Parallel()
+	ts, _ := prefetchServer(t, nil)
+	cache := pokecache.NewCache(time.Hour)
+	t.Cleanup(cache.Done)
+	out := &bytes.Buffer{}
+	c := config.Config{
+		AreaURL:    ts.URL + "/location-area/",
+		PokemonURL: ts.URL + "/pokemon/",
+		Cache:      cache,
+		Logger:     logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:        out,
+		HTTPClient: ts.Client(),
+		DataDir:    t.TempDir(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := commandPrefetch(ctx, &c)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !strings.Contains(out.String(), "run prefetch again to resume") {
+		t.Fatalf("expected resume hint, got %q", out.String())
+	}
+}
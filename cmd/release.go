@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+const releaseUsage = "usage: release <name|#instance>... [--all] | release --undo"
+
+func commandRelease(ctx context.Context, c *config.Config) error {
+	all := false
+	var args []string
+	for _, arg := range c.Args {
+		switch arg {
+		case "--all":
+			all = true
+		case "--undo":
+			return releaseUndo(c)
+		default:
+			args = append(args, arg)
+		}
+	}
+	if len(args) == 0 {
+		return writeLine(c, releaseUsage)
+	}
+	toRelease, err := releaseTargets(c, args, all)
+	if err != nil || len(toRelease) == 0 {
+		return err
+	}
+	for _, p := range toRelease {
+		if err = writeLine(c, fmt.Sprintf("  %s #%d (level %d)", p.Name, p.InstanceID, p.Level)); err != nil {
+			return err
+		}
+	}
+	ok, err := confirm(c, fmt.Sprintf("Release %d pokemon? [y/N]", len(toRelease)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return writeLine(c, "Release cancelled")
+	}
+	for _, p := range toRelease {
+		c.Pokedex.Remove(p)
+		c.Logger.Info("Released pokemon", "name", p.Name, "instance", p.InstanceID)
+	}
+	c.Released = append(c.Released, toRelease)
+	if err = writeLine(c, fmt.Sprintf("Released %d pokemon, run release --undo to bring them back", len(toRelease))); err != nil {
+		return err
+	}
+	return saveWithWarning(c)
+}
+
+// releaseTargets resolves the arguments to instances. A name picks the
+// earliest caught instance of that species, or every instance with --all.
+// Arguments that match nothing are reported and skipped.
+func releaseTargets(c *config.Config, args []string, all bool) ([]pokedex.Pokemon, error) {
+	var targets []pokedex.Pokemon
+	seen := make(map[int]bool)
+	for _, arg := range args {
+		var found []pokedex.Pokemon
+		if id, ok := pokedex.ParseInstanceID(arg); ok {
+			if p, err := c.Pokedex.GetInstance(id); err == nil {
+				found = append(found, p)
+			}
+		} else if all {
+			found = c.Pokedex.GetInstances(arg)
+		} else if p, err := c.Pokedex.GetPokemonByName(arg); err == nil {
+			found = append(found, p)
+		}
+		if len(found) == 0 {
+			if err := writeLine(c, fmt.Sprintf("you have not caught %s", arg)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, p := range found {
+			if !seen[p.InstanceID] {
+				seen[p.InstanceID] = true
+				targets = append(targets, p)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// confirm asks question and reads the answer from c.Input. Anything other
+// than y or yes, including a closed input, counts as no.
+func confirm(c *config.Config, question string) (bool, error) {
+	if err := writeLine(c, question); err != nil {
+		return false, err
+	}
+	if c.Input == nil {
+		return false, nil
+	}
+	answer, err := c.Input.Readline()
+	if err != nil {
+		c.Logger.Info("No confirmation received", "error", err)
+		return false, nil
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func releaseUndo(c *config.Config) error {
+	if len(c.Released) == 0 {
+		return writeLine(c, "nothing to undo")
+	}
+	last := c.Released[len(c.Released)-1]
+	c.Released = c.Released[:len(c.Released)-1]
+	for _, p := range last {
+		c.Pokedex.Add(p)
+	}
+	if err := writeLine(c, fmt.Sprintf("Brought back %d pokemon", len(last))); err != nil {
+		return err
+	}
+	return saveWithWarning(c)
+}
+
+func newReleaseCommand() *CliCommand {
+	return &CliCommand{
+		name:        "release",
+		description: "Release caught pokemon by name or instance number, e.g. #3 (--all for every one of a species, --undo to bring the last release back)",
+		Callback:    commandRelease,
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/logging"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+type answerReader struct {
+	answers []string
+}
+
+func (a *answerReader) Readline() (string, error) {
+	if len(a.answers) == 0 {
+		return "", io.EOF
+	}
+	answer := a.answers[0]
+	a.answers = a.answers[1:]
+	return answer, nil
+}
+
+func (a *answerReader) Close() error { return nil }
+
+func releaseConfig(answers ...string) (*config.Config, *savingPokedex, *bytes.Buffer) {
+	p := &savingPokedex{Pokedex: pokedex.NewPokedex()}
+	p.Add(pokedex.Pokemon{Name: "pidgey", Level: 3})
+	p.Add(pokedex.Pokemon{Name: "mew", Level: 30})
+	p.Add(pokedex.Pokemon{Name: "pidgey", Level: 5})
+	out := &bytes.Buffer{}
+	c := &config.Config{
+		Pokedex: p,
+		Logger:  logging.NewLogger(logging.MyHandler{Level: slog.LevelError}),
+		Out:     out,
+		Input:   &answerReader{answers: answers},
+	}
+	return c, p, out
+}
+
+func instanceIDs(pokemon []pokedex.Pokemon) []int {
+	ids := make([]int, 0, len(pokemon))
+	for _, p := range pokemon {
+		ids = append(ids, p.InstanceID)
+	}
+	return ids
+}
+
+func TestCommandRelease(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		args      []string
+		answers   []string
+		wantLeft  []int
+		wantSaves int
+		wantOut   []string
+	}{
+		{name: "usage", wantLeft: []int{1, 2, 3}, wantOut: []string{releaseUsage}},
+		{name: "earliest of species", args: []string{"pidgey"}, answers: []string{"y"}, wantLeft: []int{2, 3}, wantSaves: 1, wantOut: []string{"  pidgey #1 (level 3)", "Release 1 pokemon? [y/N]", "Released 1 pokemon"}},
+		{name: "whole species", args: []string{"pidgey", "--all"}, answers: []string{"yes"}, wantLeft: []int{2}, wantSaves: 1, wantOut: []string{"Release 2 pokemon?"}},
+		{name: "by instance", args: []string{"#3", "#2", "#3"}, answers: []string{"Y"}, wantLeft: []int{1}, wantSaves: 1, wantOut: []string{"Release 2 pokemon?"}},
+		{name: "declined", args: []string{"mew"}, answers: []string{"n"}, wantLeft: []int{1, 2, 3}, wantOut: []string{"Release cancelled"}},
+		{name: "no answer", args: []string{"mew"}, wantLeft: []int{1, 2, 3}, wantOut: []string{"Release cancelled"}},
+		{name: "not caught", args: []string{"onix", "#9"}, wantLeft: []int{1, 2, 3}, wantOut: []string{"you have not caught onix", "you have not caught #9"}},
+		{name: "bare number is not an instance", args: []string{"2"}, wantLeft: []int{1, 2, 3}, wantOut: []string{"you have not caught 2"}},
+		{name: "nothing to undo", args: []string{"--undo"}, wantLeft: []int{1, 2, 3}, wantOut: []string{"nothing to undo"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c, p, out := releaseConfig(tc.answers...)
+			c.Args = tc.args
+
+			if err := commandRelease(context.Background(), c); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if got := instanceIDs(p.GetAllPokemon()); !reflect.DeepEqual(got, tc.wantLeft) {
+				t.Fatalf("expected instances %v to remain, got %v", tc.wantLeft, got)
+			}
+			if p.saves != tc.wantSaves {
+				t.Fatalf("expected %d saves, got %d", tc.wantSaves, p.saves)
+			}
+			for _, want := range tc.wantOut {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("expected output to contain %q, got %q", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCommandReleaseUndo(t *testing.T) {
+	t.Parallel()
+
+	c, p, out := releaseConfig("y", "y")
+	release := func(args ...string) {
+		t.Helper()
+		c.Args = args
+		if err := commandRelease(context.Background(), c); err != nil {
+			t.Fatalf("release %v: expected nil error, got %v", args, err)
+		}
+	}
+
+	release("mew")
+	release("pidgey", "--all")
+	if got := p.GetAllPokemon(); len(got) != 0 {
+		t.Fatalf("expected every pokemon to be released, got %v", instanceIDs(got))
+	}
+	release("--undo")
+	if got := instanceIDs(p.GetAllPokemon()); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Fatalf("expected the pidgey to come back first, got %v", got)
+	}
+	release("--undo")
+	restored, err := p.GetInstance(2)
+	if err != nil || restored.Name != "mew" || restored.Level != 30 {
+		t.Fatalf("expected mew #2 to be restored unchanged, got %+v, %v", restored, err)
+	}
+	release("--undo")
+	if !strings.Contains(out.String(), "nothing to undo") {
+		t.Fatalf("expected the undo history to be exhausted, got %q", out.String())
+	}
+	if p.saves != 4 {
+		t.Fatalf("expected a save after every release and undo, got %d", p.saves)
+	}
+	if next := p.Add(pokedex.Pokemon{Name: "onix"}); next.InstanceID != 4 {
+		t.Fatalf("expected restored instances not to reuse IDs, got %d", next.InstanceID)
+	}
+}
+
+func TestCommandReleaseWriteError(t *testing.T) {
+	t.Parallel()
+
+	for n := 1; n <= 3; n++ {
+		c, _, _ := releaseConfig("y")
+		c.Args = []string{"mew"}
+		c.Out = &failOnWriteN{n: n, err: writeError}
+		if err := commandRelease(context.Background(), c); err != writeError {
+			t.Fatalf("write %d: expected write error, got %v", n, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+)
+
+func savePokedex(c *config.Config) error {
+	saver, ok := c.Pokedex.(domain.Saver)
+	if !ok {
+		return nil
+	}
+	err := saver.Save()
+	if err != nil {
+		c.Logger.Error("Error saving pokedex: ", "error", err)
+		return err
+	}
+	return nil
+}
+
+// saveWithWarning saves the Pokedex and tells the user, rather than failing
+// the command, when that doesn't work.
+func saveWithWarning(c *config.Config) error {
+	if saveErr := savePokedex(c); saveErr != nil {
+		return writeLine(c, fmt.Sprintf("warning: your pokedex could not be saved: %v", saveErr))
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+type NamedResource struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+type PokemonSpecies struct {
+	Id                 int            `json:"id"`
+	Name               string         `json:"name"`
+	EvolvesFromSpecies *NamedResource `json:"evolves_from_species"`
+	EvolutionChain     struct {
+		Url string `json:"url"`
+	} `json:"evolution_chain"`
+	Varieties []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   NamedResource `json:"pokemon"`
+	} `json:"varieties"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   NamedResource `json:"language"`
+		Version    NamedResource `json:"version"`
+	} `json:"flavor_text_entries"`
+}
+
+type EvolutionChain struct {
+	Id    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+type ChainLink struct {
+	Species          NamedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionDetail is one way to evolve. PokeAPI sends null for every
+// condition that doesn't apply.
+type EvolutionDetail struct {
+	Trigger       NamedResource  `json:"trigger"`
+	MinLevel      int            `json:"min_level"`
+	MinHappiness  int            `json:"min_happiness"`
+	MinAffection  int            `json:"min_affection"`
+	TimeOfDay     string         `json:"time_of_day"`
+	Item          *NamedResource `json:"item"`
+	HeldItem      *NamedResource `json:"held_item"`
+	KnownMove     *NamedResource `json:"known_move"`
+	KnownMoveType *NamedResource `json:"known_move_type"`
+	Location      *NamedResource `json:"location"`
+	TradeSpecies  *NamedResource `json:"trade_species"`
+}
+
+func getSpecies(ctx context.Context, c *config.Config, url string) (PokemonSpecies, error) {
+	var species PokemonSpecies
+	body, err := getBodyWithCache(ctx, c, url)
+	if err != nil {
+		return species, err
+	}
+	err = json.Unmarshal(body, &species)
+	if err != nil {
+		c.Logger.Error("Error parsing response: ", "url", url, "error", err)
+	}
+	return species, err
+}
+
+func getEvolutionChain(ctx context.Context, c *config.Config, url string) (EvolutionChain, error) {
+	var chain EvolutionChain
+	body, err := getBodyWithCache(ctx, c, url)
+	if err != nil {
+		return chain, err
+	}
+	err = json.Unmarshal(body, &chain)
+	if err != nil {
+		c.Logger.Error("Error parsing response: ", "url", url, "error", err)
+	}
+	return chain, err
+}
+
+// defaultPokemonURL returns the pokemon URL of the species' default form,
+// or "" if PokeAPI doesn't list one.
+func (s PokemonSpecies) defaultPokemonURL() string {
+	for _, v := range s.Varieties {
+		if v.IsDefault {
+			return v.Pokemon.Url
+		}
+	}
+	return ""
+}
+
+// find returns the link for species in the tree rooted at l, or nil.
+func (l *ChainLink) find(species string) *ChainLink {
+	if l.Species.Name == species {
+		return l
+	}
+	for i := range l.EvolvesTo {
+		if found := l.EvolvesTo[i].find(species); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// flavorText returns the newest Pokedex entry in language, flattened to one
+// line. PokeAPI keeps the line and page breaks of the games.
+func (s PokemonSpecies) flavorText(language string) string {
+	for i := len(s.FlavorTextEntries) - 1; i >= 0; i-- {
+		entry := s.FlavorTextEntries[i]
+		if entry.Language.Name == language {
+			return strings.Join(strings.Fields(entry.FlavorText), " ")
+		}
+	}
+	return ""
+}
+
+// printFlavorText writes the species' Pokedex entry. It is skipped for
+// pokemon caught before the species reference was recorded, and when the
+// species can't be fetched, since the rest of the view doesn't depend on it.
+func printFlavorText(ctx context.Context, c *config.Config, p pokedex.Pokemon) error {
+	if p.Species.Url == "" {
+		return nil
+	}
+	species, err := getSpecies(ctx, c, p.Species.Url)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.Logger.Info("Skipping flavor text", "url", p.Species.Url, "error", err)
+		return nil
+	}
+	text := species.flavorText(c.Language)
+	if text == "" {
+		return nil
+	}
+	return writeLine(c, "Pokedex entry: "+text)
+}
@@ -0,0 +1,54 @@
+// Package atomicfile replaces files so that a crash leaves either the old
+// contents or the new ones, never a partial write.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write writes data to a temporary file next to path, fsyncs it and renames
+// it over path, creating the directory if needed.
+func Write(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func(err error) error {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err = os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	SyncDir(dir)
+	return nil
+}
+
+// SyncDir makes a rename in dir durable. Not every platform supports syncing
+// a directory, so failures are ignored.
+func SyncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
@@ -0,0 +1,60 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		existing string
+	}{
+		{name: "new file"},
+		{name: "replaces existing file", existing: "old"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "nested", "pokedex.json")
+			if tc.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+				if err := os.WriteFile(path, []byte(tc.existing), 0o644); err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+			}
+
+			if err := Write(path, []byte("new")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != "new" {
+				t.Fatalf("expected %q, got %q, %v", "new", string(data), err)
+			}
+			files, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if len(files) != 1 {
+				t.Fatalf("expected no temporary files left behind, got %d files", len(files))
+			}
+		})
+	}
+}
+
+func TestWriteFailsWhenDirectoryCannotBeCreated(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := Write(filepath.Join(file, "pokedex.json"), []byte("new")); err == nil {
+		t.Fatal("expected error when the parent is a file")
+	}
+}
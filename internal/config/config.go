@@ -4,23 +4,42 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/Flarenzy/Pokedex/internal"
 	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+	"github.com/Flarenzy/Pokedex/internal/singleflight"
+	"github.com/Flarenzy/Pokedex/internal/typechart"
 )
 
 type Config struct {
-	Next        string
-	Previous    string
-	AreaURL     string
-	PokemonURL  string
-	Args        []string
-	Pokedex     domain.Pokedexer
-	Cache       domain.Cacher
-	Logger      *slog.Logger
-	Out         io.Writer
-	HTTPClient  domain.HTTPClient
-	RandFloat64 func() float64
+	Next           string
+	Previous       string
+	AreaURL        string
+	PokemonURL     string
+	SpeciesURL     string
+	TypeURL        string
+	Language       string
+	Args           []string
+	Pokedex        domain.Pokedexer
+	Cache          domain.Cacher
+	Logger         *slog.Logger
+	Out            io.Writer
+	HTTPClient     domain.HTTPClient
+	RandFloat64    func() float64
+	Offline        bool
+	FixturesDir    string
+	DataDir        string
+	Compactor      domain.Compactor
+	Encounters     map[string]pokedex.Encounter
+	Input          domain.LineReader
+	Released       [][]pokedex.Pokemon
+	PageSize       int
+	TypeChart      *typechart.Chart
+	CommandTimeout time.Duration
+	MaxWorkers     int
+	Inflight       *singleflight.Group[[]byte]
 }
 
 func NewConfig(
@@ -35,6 +54,9 @@ func NewConfig(
 		Previous:    "",
 		AreaURL:     internal.FirstURL,
 		PokemonURL:  internal.SecondURL,
+		SpeciesURL:  internal.SpeciesURL,
+		TypeURL:     internal.TypeURL,
+		Language:    "en",
 		Args:        []string{},
 		Cache:       cache,
 		Logger:      logger,
@@ -42,5 +64,9 @@ func NewConfig(
 		Out:         os.Stdout,
 		HTTPClient:  client,
 		RandFloat64: randFloat64,
+		MaxWorkers:  4,
+		PageSize:    20,
+		TypeChart:   typechart.New(),
+		Inflight:    &singleflight.Group[[]byte]{},
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/Flarenzy/Pokedex/internal"
+	"github.com/Flarenzy/Pokedex/internal/domain"
 	"github.com/Flarenzy/Pokedex/internal/pokedex"
 )
 
@@ -15,16 +16,25 @@ type stubCache struct{}
 
 func (s stubCache) Get(key string) ([]byte, error)   { return nil, nil }
 func (s stubCache) Add(key string, val []byte) error { return nil }
+func (s stubCache) List(prefix string) ([]domain.CacheEntry, error) {
+	return nil, nil
+}
+func (s stubCache) Purge(prefix string) (int, error) { return 0, nil }
+func (s stubCache) Clear() error                     { return nil }
 func (s stubCache) Done()                            {}
 
 type stubPokedex struct{}
 
-func (s stubPokedex) Add(p pokedex.Pokemon)            {}
-func (s stubPokedex) Remove(p pokedex.Pokemon)         {}
-func (s stubPokedex) GetAllPokemon() []pokedex.Pokemon { return nil }
+func (s stubPokedex) Add(p pokedex.Pokemon) pokedex.Pokemon      { return p }
+func (s stubPokedex) Remove(p pokedex.Pokemon)                   {}
+func (s stubPokedex) GetAllPokemon() []pokedex.Pokemon           { return nil }
+func (s stubPokedex) GetInstances(name string) []pokedex.Pokemon { return nil }
 func (s stubPokedex) GetPokemonByName(name string) (pokedex.Pokemon, error) {
 	return pokedex.Pokemon{}, nil
 }
+func (s stubPokedex) GetInstance(id int) (pokedex.Pokemon, error) {
+	return pokedex.Pokemon{}, nil
+}
 
 type stubHTTPClient struct{}
 
@@ -48,6 +58,12 @@ func TestNewConfig(t *testing.T) {
 	if c.PokemonURL != internal.SecondURL {
 		t.Fatalf("expected default second URL, got %q", c.PokemonURL)
 	}
+	if c.TypeURL != internal.TypeURL || c.TypeChart == nil {
+		t.Fatalf("expected default type URL and an empty type chart, got %q", c.TypeURL)
+	}
+	if c.SpeciesURL != internal.SpeciesURL || c.Language != "en" {
+		t.Fatalf("expected default species URL and language, got %q, %q", c.SpeciesURL, c.Language)
+	}
 	if c.Previous != "" {
 		t.Fatalf("expected empty previous, got %q", c.Previous)
 	}
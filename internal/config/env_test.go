@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("POKEDEX_TEST_INT", "7")
+	t.Setenv("POKEDEX_TEST_BAD_INT", "seven")
+	t.Setenv("POKEDEX_TEST_DURATION", "1m30s")
+	t.Setenv("POKEDEX_TEST_BAD_DURATION", "soon")
+	t.Setenv("POKEDEX_TEST_BOOL", "true")
+	t.Setenv("POKEDEX_TEST_FLOAT", "2.5")
+
+	if got := IntFromEnv("POKEDEX_TEST_INT", 1); got != 7 {
+		t.Fatalf("expected 7, got %d", got)
+	}
+	if got := IntFromEnv("POKEDEX_TEST_BAD_INT", 1); got != 1 {
+		t.Fatalf("expected default 1, got %d", got)
+	}
+	if got := IntFromEnv("POKEDEX_TEST_UNSET", 3); got != 3 {
+		t.Fatalf("expected default 3, got %d", got)
+	}
+	if got := FloatFromEnv("POKEDEX_TEST_FLOAT", 1); got != 2.5 {
+		t.Fatalf("expected 2.5, got %v", got)
+	}
+	if got := FloatFromEnv("POKEDEX_TEST_UNSET", 1); got != 1 {
+		t.Fatalf("expected default 1, got %v", got)
+	}
+	if got := DurationFromEnv("POKEDEX_TEST_DURATION", time.Second); got != 90*time.Second {
+		t.Fatalf("expected 1m30s, got %v", got)
+	}
+	if got := DurationFromEnv("POKEDEX_TEST_BAD_DURATION", time.Second); got != time.Second {
+		t.Fatalf("expected default 1s, got %v", got)
+	}
+	if got := BoolFromEnv("POKEDEX_TEST_BOOL", false); !got {
+		t.Fatal("expected true")
+	}
+	if got := BoolFromEnv("POKEDEX_TEST_UNSET", false); got {
+		t.Fatal("expected default false")
+	}
+}
@@ -2,3 +2,5 @@ package internal
 
 const FirstURL = "https://pokeapi.co/api/v2/location-area/"
 const SecondURL = "https://pokeapi.co/api/v2/pokemon/"
+const SpeciesURL = "https://pokeapi.co/api/v2/pokemon-species/"
+const TypeURL = "https://pokeapi.co/api/v2/type/"
@@ -1,7 +1,57 @@
 package domain
 
+import "time"
+
 type Cacher interface {
 	Get(key string) ([]byte, error)
 	Add(key string, val []byte) error
+	// List returns the entries whose keys start with prefix, sorted by key.
+	List(prefix string) ([]CacheEntry, error)
+	// Purge removes the entries whose keys start with prefix and reports
+	// how many were removed.
+	Purge(prefix string) (int, error)
+	Clear() error
 	Done()
 }
+
+// CacheEntry describes a cached value without its body. ExpiresAt is zero
+// for entries that never expire.
+type CacheEntry struct {
+	Key       string
+	Size      int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+func (v Validators) Empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// Revalidator is implemented by caches that keep expired entries around so
+// they can be revalidated with a conditional request instead of refetched.
+type Revalidator interface {
+	AddWithValidators(key string, val []byte, v Validators) error
+	GetStale(key string) ([]byte, Validators, error)
+	Refresh(key string) error
+}
+
+type CacheStats struct {
+	Hits            uint64
+	Misses          uint64
+	Revalidations   uint64
+	EvictedTTL      uint64
+	EvictedCapacity uint64
+	// StoredBytes is what the cached bodies take up after compression and
+	// UncompressedBytes what they would take up without it.
+	StoredBytes       uint64
+	UncompressedBytes uint64
+}
+
+type StatsReporter interface {
+	Stats() CacheStats
+}
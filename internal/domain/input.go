@@ -0,0 +1,8 @@
+package domain
+
+// LineReader reads one line of user input at a time. Commands use it to ask
+// for confirmation while the REPL is running.
+type LineReader interface {
+	Readline() (string, error)
+	Close() error
+}
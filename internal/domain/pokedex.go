@@ -3,8 +3,14 @@ package domain
 import "github.com/Flarenzy/Pokedex/internal/pokedex"
 
 type Pokedexer interface {
-	Add(p pokedex.Pokemon)
+	Add(p pokedex.Pokemon) pokedex.Pokemon
 	Remove(p pokedex.Pokemon)
 	GetAllPokemon() []pokedex.Pokemon
 	GetPokemonByName(name string) (pokedex.Pokemon, error)
+	GetInstances(name string) []pokedex.Pokemon
+	GetInstance(id int) (pokedex.Pokemon, error)
+}
+
+type Saver interface {
+	Save() error
 }
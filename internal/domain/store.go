@@ -0,0 +1,7 @@
+package domain
+
+// Compactor is implemented by storage that can reclaim space left behind by
+// overwritten and deleted records. Compact reports the size before and after.
+type Compactor interface {
+	Compact() (before int64, after int64, err error)
+}
@@ -0,0 +1,68 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrNoFixture = errors.New("no fixture for url")
+
+const defaultPageSize = 20
+
+// Dir is a directory of PokeAPI JSON dumps laid out like testdata/pokeapi:
+//
+//	location-area/          -> location-area-page1.json
+//	location-area/?offset=20 -> location-area-page2.json
+//	location-area/1/        -> location-area-1.json
+//	pokemon/mew             -> pokemon-mew.json
+type Dir string
+
+func (d Dir) Load(rawURL string) ([]byte, error) {
+	name, err := FileName(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(filepath.Join(string(d), name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNoFixture, rawURL)
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
+func FileName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrNoFixture, rawURL)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	start := -1
+	for i, s := range segments {
+		if s == "v2" {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 || start >= len(segments) || len(segments)-start > 2 {
+		return "", fmt.Errorf("%w: %s", ErrNoFixture, rawURL)
+	}
+	resource := segments[start]
+	if len(segments)-start == 2 {
+		return resource + "-" + segments[start+1] + ".json", nil
+	}
+
+	query := u.Query()
+	limit := defaultPageSize
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	return fmt.Sprintf("%s-page%d.json", resource, offset/limit+1), nil
+}
@@ -0,0 +1,55 @@
+package fixtures
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url     string
+		want    string
+		wantErr error
+	}{
+		{url: "https://pokeapi.co/api/v2/location-area/", want: "location-area-page1.json"},
+		{url: "https://pokeapi.co/api/v2/location-area/?offset=20&limit=20", want: "location-area-page2.json"},
+		{url: "https://pokeapi.co/api/v2/location-area/?offset=40&limit=10", want: "location-area-page5.json"},
+		{url: "https://pokeapi.co/api/v2/location-area/1/", want: "location-area-1.json"},
+		{url: "http://127.0.0.1:8080/api/v2/pokemon/mew", want: "pokemon-mew.json"},
+		{url: "https://pokeapi.co/somewhere/else", wantErr: ErrNoFixture},
+		{url: "https://pokeapi.co/api/v2/pokemon/mew/extra", wantErr: ErrNoFixture},
+		{url: "://bad", wantErr: ErrNoFixture},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			got, err := FileName(tc.url)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDirLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := Dir("../../testdata/pokeapi")
+	body, err := dir.Load("https://pokeapi.co/api/v2/pokemon/mew")
+	if err != nil {
+		t.Fatalf("expected fixture, got %v", err)
+	}
+	if len(body) == 0 {
+		t.Fatal("expected non-empty fixture")
+	}
+
+	_, err = dir.Load("https://pokeapi.co/api/v2/pokemon/missingno")
+	if !errors.Is(err, ErrNoFixture) {
+		t.Fatalf("expected ErrNoFixture, got %v", err)
+	}
+}
@@ -1,8 +1,13 @@
 package http
 
-import "net/http"
+import (
+	"net/http"
+	"time"
 
-import "github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+)
+
+const defaultTimeout = 30 * time.Second
 
 type HTTPClienter = domain.HTTPClient
 
@@ -12,7 +17,7 @@ type DefaultHTTPClient struct {
 
 func NewDefaultHTTPClient() *DefaultHTTPClient {
 	return &DefaultHTTPClient{
-		c: &http.Client{},
+		c: &http.Client{Timeout: defaultTimeout},
 	}
 }
 
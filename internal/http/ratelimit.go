@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenBucket hands out up to burst tokens at once and refills at rate
+// tokens per second. It is safe for concurrent use; callers that have to
+// wait are queued in the order they reserved a token.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *TokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+}
+
+// Wait blocks until a token is available and returns how long it waited.
+// A bucket with a non-positive rate never blocks.
+func (b *TokenBucket) Wait(ctx context.Context) (time.Duration, error) {
+	if b.rate <= 0 {
+		return 0, nil
+	}
+	wait := b.reserve()
+	if wait == 0 {
+		return 0, nil
+	}
+	if err := b.sleep(ctx, wait); err != nil {
+		b.cancel()
+		return 0, err
+	}
+	return wait, nil
+}
+
+type RateLimitedClient struct {
+	next   HTTPClienter
+	bucket *TokenBucket
+	logger *slog.Logger
+}
+
+func NewRateLimitedClient(next HTTPClienter, bucket *TokenBucket, logger *slog.Logger) *RateLimitedClient {
+	return &RateLimitedClient{
+		next:   next,
+		bucket: bucket,
+		logger: logger,
+	}
+}
+
+func (c *RateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	waited, err := c.bucket.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if waited > 0 {
+		c.logger.Info("Rate limited request", "url", req.URL.String(), "wait", waited)
+	}
+	return c.next.Do(req)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestBucket(rate float64, burst int) (*TokenBucket, *time.Time) {
+	b := NewTokenBucket(rate, burst)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.last = now
+	b.now = func() time.Time { return now }
+	b.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return b, &now
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	t.Parallel()
+
+	b, now := newTestBucket(2, 2)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		waited, err := b.Wait(ctx)
+		if err != nil || waited != 0 {
+			t.Fatalf("expected burst token %d without waiting, got %v, %v", i, waited, err)
+		}
+	}
+	waited, err := b.Wait(ctx)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if waited != 500*time.Millisecond {
+		t.Fatalf("expected 500ms wait, got %v", waited)
+	}
+	waited, _ = b.Wait(ctx)
+	if waited != time.Second {
+		t.Fatalf("expected queued caller to wait 1s, got %v", waited)
+	}
+
+	*now = now.Add(10 * time.Second)
+	waited, _ = b.Wait(ctx)
+	if waited != 0 {
+		t.Fatalf("expected refilled bucket, got wait %v", waited)
+	}
+	if b.tokens != 1 {
+		t.Fatalf("expected refill to be capped at burst, got %v tokens left", b.tokens)
+	}
+}
+
+func TestTokenBucketWaitCancelled(t *testing.T) {
+	t.Parallel()
+
+	b, _ := newTestBucket(1, 1)
+	_, _ = b.Wait(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := b.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if b.tokens != 0 {
+		t.Fatalf("expected cancelled reservation to be returned, got %v tokens", b.tokens)
+	}
+}
+
+func TestTokenBucketUnlimited(t *testing.T) {
+	t.Parallel()
+
+	b := NewTokenBucket(0, 1)
+	for i := 0; i < 100; i++ {
+		if waited, err := b.Wait(context.Background()); err != nil || waited != 0 {
+			t.Fatalf("expected unlimited bucket not to wait, got %v, %v", waited, err)
+		}
+	}
+}
+
+type countingClient struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestRateLimitedClientConcurrent(t *testing.T) {
+	t.Parallel()
+
+	next := &countingClient{}
+	c := NewRateLimitedClient(next, NewTokenBucket(200, 5), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, "https://example.test/", nil)
+			if err != nil {
+				t.Errorf("new request failed: %v", err)
+				return
+			}
+			if _, err = c.Do(req); err != nil {
+				t.Errorf("Do failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if next.calls != 25 {
+		t.Fatalf("expected 25 calls, got %d", next.calls)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected 20 requests beyond the burst to take about 100ms, took %v", elapsed)
+	}
+}
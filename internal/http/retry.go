@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	MaxElapsed  time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		MaxElapsed:  30 * time.Second,
+	}
+}
+
+// RetryClient retries idempotent requests that failed with a transient
+// network error or a 429/5xx response, sleeping with full-jitter exponential
+// backoff between attempts. A Retry-After header overrides the backoff. When
+// attempts or elapsed time run out the last response or error is returned
+// unchanged.
+type RetryClient struct {
+	next   HTTPClienter
+	policy RetryPolicy
+	logger *slog.Logger
+	now    func() time.Time
+	jitter func() float64
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func NewRetryClient(next HTTPClienter, policy RetryPolicy, logger *slog.Logger) *RetryClient {
+	return &RetryClient{
+		next:   next,
+		policy: policy,
+		logger: logger,
+		now:    time.Now,
+		jitter: rand.Float64,
+		sleep:  sleepContext,
+	}
+}
+
+func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return c.next.Do(req)
+	}
+	start := c.now()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.next.Do(req)
+		if attempt >= c.policy.MaxAttempts || !c.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := c.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp, c.now()); ok {
+			wait = retryAfter
+		}
+		if c.now().Sub(start)+wait > c.policy.MaxElapsed {
+			c.logger.Warn("Giving up retrying request", "url", req.URL.String(), "attempt", attempt, "wait", wait)
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		c.logger.Info("Retrying request", "url", req.URL.String(), "attempt", attempt, "wait", wait, "error", err)
+		if sleepErr := c.sleep(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+func (c *RetryClient) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		return isTransient(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func (c *RetryClient) backoff(attempt int) time.Duration {
+	ceiling := c.policy.BaseDelay << (attempt - 1)
+	if ceiling > c.policy.MaxDelay || ceiling <= 0 {
+		ceiling = c.policy.MaxDelay
+	}
+	return time.Duration(c.jitter() * float64(ceiling))
+}
+
+// isTransient reports whether err is worth another attempt. Every transport
+// failure comes wrapped in a *url.Error, which is a net.Error, so only
+// timeouts count among those; a bad scheme, a TLS failure or an unknown host
+// fails the same way next time. io.EOF is what the transport returns when a
+// kept-alive connection was closed by the server before it answered.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func parseRetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type scriptedResult struct {
+	status     int
+	retryAfter string
+	err        error
+}
+
+type scriptedClient struct {
+	results []scriptedResult
+	calls   int
+}
+
+func (s *scriptedClient) Do(req *http.Request) (*http.Response, error) {
+	r := s.results[s.calls]
+	s.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	header := make(http.Header)
+	if r.retryAfter != "" {
+		header.Set("Retry-After", r.retryAfter)
+	}
+	return &http.Response{
+		StatusCode: r.status,
+		Body:       io.NopCloser(strings.NewReader("body")),
+		Header:     header,
+		Request:    req,
+	}, nil
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func urlError(err error) error {
+	return &url.Error{Op: "Get", URL: "https://example.test/api/v2/pokemon/mew", Err: err}
+}
+
+func newTestRetryClient(next HTTPClienter, policy RetryPolicy) (*RetryClient, *[]time.Duration) {
+	c := NewRetryClient(next, policy, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+	c.now = func() time.Time { return now }
+	c.jitter = func() float64 { return 1.0 }
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return c, &waits
+}
+
+func TestRetryClientDo(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, MaxElapsed: 10 * time.Second}
+	tests := []struct {
+		name       string
+		method     string
+		results    []scriptedResult
+		wantStatus int
+		wantErr    bool
+		wantCalls  int
+		wantWaits  []time.Duration
+	}{
+		{
+			name:       "success on first attempt",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 200}},
+			wantStatus: 200,
+			wantCalls:  1,
+		},
+		{
+			name:       "retries server errors with exponential backoff",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 503}, {status: 502}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  3,
+			wantWaits:  []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:       "honors retry-after seconds",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 429, retryAfter: "2"}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+			wantWaits:  []time.Duration{2 * time.Second},
+		},
+		{
+			name:       "honors retry-after date",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 429, retryAfter: "Mon, 01 Jan 2024 00:00:03 GMT"}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+			wantWaits:  []time.Duration{3 * time.Second},
+		},
+		{
+			name:       "retries transient network errors",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{err: syscall.ECONNRESET}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+			wantWaits:  []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:       "retries wrapped connection refused",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{err: urlError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED})}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+			wantWaits:  []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:       "retries a connection closed before the response",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{err: urlError(io.EOF)}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+			wantWaits:  []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:       "retries timeouts",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{err: urlError(timeoutError{})}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+			wantWaits:  []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:      "does not retry permanent errors",
+			method:    http.MethodGet,
+			results:   []scriptedResult{{err: errors.New("unsupported protocol scheme")}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "does not retry a bad scheme",
+			method:    http.MethodGet,
+			results:   []scriptedResult{{err: urlError(errors.New(`unsupported protocol scheme "htp"`))}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "does not retry unknown hosts",
+			method:    http.MethodGet,
+			results:   []scriptedResult{{err: urlError(&net.DNSError{Err: "no such host", Name: "pokeapi.test", IsNotFound: true})}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:       "does not retry client errors",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 404}},
+			wantStatus: 404,
+			wantCalls:  1,
+		},
+		{
+			name:       "does not retry non-idempotent methods",
+			method:     http.MethodPost,
+			results:    []scriptedResult{{status: 503}},
+			wantStatus: 503,
+			wantCalls:  1,
+		},
+		{
+			name:       "caps attempts",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 500}, {status: 500}, {status: 500}, {status: 500}},
+			wantStatus: 500,
+			wantCalls:  4,
+			wantWaits:  []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:       "caps elapsed time",
+			method:     http.MethodGet,
+			results:    []scriptedResult{{status: 429, retryAfter: "60"}},
+			wantStatus: 429,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next := &scriptedClient{results: tc.results}
+			c, waits := newTestRetryClient(next, policy)
+			req, err := http.NewRequest(tc.method, "https://example.test/api/v2/pokemon/mew", nil)
+			if err != nil {
+				t.Fatalf("new request failed: %v", err)
+			}
+
+			resp, err := c.Do(req)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+				if resp.StatusCode != tc.wantStatus {
+					t.Fatalf("expected status %d, got %d", tc.wantStatus, resp.StatusCode)
+				}
+			}
+			if next.calls != tc.wantCalls {
+				t.Fatalf("expected %d calls, got %d", tc.wantCalls, next.calls)
+			}
+			if len(*waits) != len(tc.wantWaits) {
+				t.Fatalf("expected waits %v, got %v", tc.wantWaits, *waits)
+			}
+			for i := range tc.wantWaits {
+				if (*waits)[i] != tc.wantWaits[i] {
+					t.Fatalf("expected waits %v, got %v", tc.wantWaits, *waits)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryClientBackoffCapsAtMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestRetryClient(&scriptedClient{}, RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second})
+	if got := c.backoff(10); got != 3*time.Second {
+		t.Fatalf("expected backoff capped at 3s, got %v", got)
+	}
+	c.jitter = func() float64 { return 0.5 }
+	if got := c.backoff(1); got != 500*time.Millisecond {
+		t.Fatalf("expected jittered backoff of 500ms, got %v", got)
+	}
+}
+
+func TestRetryClientStopsWhenContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	next := &scriptedClient{results: []scriptedResult{{status: 503}, {status: 200}}}
+	c := NewRetryClient(next, DefaultRetryPolicy(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.test/", nil)
+	if err != nil {
+		t.Fatalf("new request failed: %v", err)
+	}
+
+	_, err = c.Do(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected 1 call, got %d", next.calls)
+	}
+}
@@ -0,0 +1,466 @@
+// Package kvstore is a small embedded key-value store kept in a single
+// append-only log file.
+//
+// Every write appends a record of the form
+//
+//	crc32 | op | key length | value length | key | value
+//
+// where the CRC covers everything after itself. The index of live keys is
+// rebuilt by replaying the log on Open. A record that is cut short or fails
+// its checksum at the end of the log is the tail of an interrupted write, so
+// the log is truncated back to the last good record. Anywhere else it is
+// corruption, and Open refuses the log rather than drop what follows.
+// Compact rewrites the log with only the live records.
+package kvstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/atomicfile"
+)
+
+var (
+	ErrNotFound = errors.New("key not found")
+	ErrClosed   = errors.New("store is closed")
+	// ErrCorrupt means a damaged record sits before valid ones. The log is
+	// left untouched so it can be repaired or restored.
+	ErrCorrupt = errors.New("store is corrupt")
+	// ErrLocked means another process has the store open.
+	ErrLocked = errors.New("store is in use by another process")
+)
+
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+
+	headerSize   = 4 + 1 + 4 + 4
+	maxKeySize   = 1 << 16
+	maxValueSize = 1 << 30
+)
+
+// SyncPolicy controls when writes are flushed to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs after every write.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval fsyncs in the background at a fixed interval, so at most
+	// one interval of writes can be lost in a crash.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system and Close.
+	SyncNever
+)
+
+const defaultSyncInterval = time.Second
+
+type Option func(*Store)
+
+func WithSyncPolicy(p SyncPolicy) Option {
+	return func(s *Store) { s.policy = p }
+}
+
+// WithSyncInterval sets how often SyncInterval flushes.
+func WithSyncInterval(d time.Duration) Option {
+	return func(s *Store) { s.interval = d }
+}
+
+type location struct {
+	offset int64
+	size   int
+}
+
+func (l location) recordSize(key string) int64 {
+	return int64(headerSize + len(key) + l.size)
+}
+
+type Stats struct {
+	Keys      int
+	FileBytes int64
+	LiveBytes int64
+	// Recovered is the number of bytes of a torn tail dropped on Open.
+	Recovered int64
+}
+
+type Store struct {
+	path      string
+	mu        sync.RWMutex
+	lock      *os.File
+	f         *os.File
+	index     map[string]location
+	size      int64
+	live      int64
+	recovered int64
+	dirty     bool
+	closed    bool
+	policy    SyncPolicy
+	interval  time.Duration
+	done      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+func Open(path string, opts ...Option) (*Store, error) {
+	s := &Store{
+		path:     path,
+		policy:   SyncInterval,
+		interval: defaultSyncInterval,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := s.acquireLock(); err != nil {
+		return nil, err
+	}
+	if err := s.open(); err != nil {
+		_ = s.lock.Close()
+		return nil, err
+	}
+	if s.policy == SyncInterval && s.interval > 0 {
+		s.wg.Add(1)
+		go s.syncLoop()
+	}
+	return s, nil
+}
+
+// acquireLock locks a file next to the log for as long as the store is open,
+// so a second process can't append over this one's records. The log itself
+// isn't locked because Compact replaces it.
+func (s *Store) acquireLock() error {
+	f, err := os.OpenFile(s.path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if err = lockFile(f); err != nil {
+		_ = f.Close()
+		if errors.Is(err, ErrLocked) {
+			return fmt.Errorf("%w: %s", ErrLocked, s.path)
+		}
+		return fmt.Errorf("locking %s: %w", s.path, err)
+	}
+	s.lock = f
+	return nil
+}
+
+func (s *Store) open() error {
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	s.f = f
+	if err = s.replay(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return nil
+}
+
+// replay rebuilds the index from the log and truncates a torn tail. A bad
+// record with a good one after it is corruption rather than an interrupted
+// write, and Open fails instead of throwing the later records away.
+func (s *Store) replay() error {
+	info, err := s.f.Stat()
+	if err != nil {
+		return err
+	}
+	s.index = make(map[string]location)
+	s.live = 0
+	var offset int64
+	for {
+		op, key, valLen, ok := readRecord(s.f, offset, info.Size())
+		if !ok {
+			break
+		}
+		s.dropLive(key)
+		if op == opPut {
+			loc := location{offset: offset + headerSize + int64(len(key)), size: valLen}
+			s.index[key] = loc
+			s.live += loc.recordSize(key)
+		}
+		offset += int64(headerSize + len(key) + valLen)
+	}
+	s.size = offset
+	if offset < info.Size() {
+		rest := make([]byte, info.Size()-offset)
+		if _, err = s.f.ReadAt(rest, offset); err != nil {
+			return err
+		}
+		if recordAfter(bytes.NewReader(rest), 1, int64(len(rest))) {
+			return fmt.Errorf("%w: %s has a bad record at offset %d", ErrCorrupt, s.path, offset)
+		}
+		s.recovered = info.Size() - offset
+		if err = s.f.Truncate(offset); err != nil {
+			return fmt.Errorf("truncating torn tail of %s: %w", s.path, err)
+		}
+		return s.f.Sync()
+	}
+	return nil
+}
+
+// readRecord decodes the record at offset. ok is false when the record is
+// cut short by size or fails its checksum.
+func readRecord(r io.ReaderAt, offset, size int64) (op byte, key string, valLen int, ok bool) {
+	header := make([]byte, headerSize)
+	if offset+headerSize > size {
+		return 0, "", 0, false
+	}
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return 0, "", 0, false
+	}
+	op = header[4]
+	keyLen := binary.BigEndian.Uint32(header[5:9])
+	vLen := binary.BigEndian.Uint32(header[9:13])
+	if (op != opPut && op != opDelete) || keyLen > maxKeySize || vLen > maxValueSize {
+		return 0, "", 0, false
+	}
+	if offset+headerSize+int64(keyLen)+int64(vLen) > size {
+		return 0, "", 0, false
+	}
+	body := make([]byte, int(keyLen)+int(vLen))
+	if _, err := r.ReadAt(body, offset+headerSize); err != nil {
+		return 0, "", 0, false
+	}
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[4:])
+	_, _ = crc.Write(body)
+	if crc.Sum32() != binary.BigEndian.Uint32(header[:4]) {
+		return 0, "", 0, false
+	}
+	return op, string(body[:keyLen]), int(vLen), true
+}
+
+// recordAfter reports whether a complete, valid record starts anywhere
+// from offset on.
+func recordAfter(r io.ReaderAt, offset, size int64) bool {
+	for ; offset+headerSize <= size; offset++ {
+		if _, _, _, ok := readRecord(r, offset, size); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) dropLive(key string) {
+	if old, ok := s.index[key]; ok {
+		s.live -= old.recordSize(key)
+		delete(s.index, key)
+	}
+}
+
+func encodeRecord(op byte, key string, val []byte) []byte {
+	buf := make([]byte, headerSize+len(key)+len(val))
+	buf[4] = op
+	binary.BigEndian.PutUint32(buf[5:9], uint32(len(key)))
+	binary.BigEndian.PutUint32(buf[9:13], uint32(len(val)))
+	copy(buf[headerSize:], key)
+	copy(buf[headerSize+len(key):], val)
+	binary.BigEndian.PutUint32(buf[:4], crc32.ChecksumIEEE(buf[4:]))
+	return buf
+}
+
+// appendRecord must be called with s.mu held.
+func (s *Store) appendRecord(op byte, key string, val []byte) error {
+	if s.closed {
+		return ErrClosed
+	}
+	if len(key) > maxKeySize || len(val) > maxValueSize {
+		return fmt.Errorf("record for %q is too large", key)
+	}
+	record := encodeRecord(op, key, val)
+	if _, err := s.f.WriteAt(record, s.size); err != nil {
+		// Don't leave a partial record for the next write to land after.
+		_ = s.f.Truncate(s.size)
+		return err
+	}
+	offset := s.size
+	s.size += int64(len(record))
+	s.dropLive(key)
+	if op == opPut {
+		loc := location{offset: offset + headerSize + int64(len(key)), size: len(val)}
+		s.index[key] = loc
+		s.live += loc.recordSize(key)
+	}
+	if s.policy == SyncAlways {
+		return s.f.Sync()
+	}
+	s.dirty = true
+	return nil
+}
+
+func (s *Store) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	loc, ok := s.index[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	val := make([]byte, loc.size)
+	if _, err := s.f.ReadAt(val, loc.offset); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s *Store) Put(key string, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.appendRecord(opPut, key, val)
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.index[key]; !ok {
+		return nil
+	}
+	return s.appendRecord(opDelete, key, nil)
+}
+
+// Keys returns the live keys that start with prefix, sorted.
+func (s *Store) Keys(prefix string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0)
+	for key := range s.index {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Store) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sync()
+}
+
+func (s *Store) sync() error {
+	if s.closed || !s.dirty {
+		return nil
+	}
+	if err := s.f.Sync(); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+func (s *Store) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Stats{
+		Keys:      len(s.index),
+		FileBytes: s.size,
+		LiveBytes: s.live,
+		Recovered: s.recovered,
+	}
+}
+
+// Compact rewrites the log with only the live records and swaps it in with
+// a rename, so a crash during compaction leaves the old log intact. It
+// returns the file size before and after.
+func (s *Store) Compact() (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, 0, ErrClosed
+	}
+	before := s.size
+	tmpPath := s.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return before, before, err
+	}
+	cleanup := func(err error) (int64, int64, error) {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return before, before, err
+	}
+
+	keys := make([]string, 0, len(s.index))
+	for key := range s.index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var offset int64
+	for _, key := range keys {
+		loc := s.index[key]
+		val := make([]byte, loc.size)
+		if _, err = s.f.ReadAt(val, loc.offset); err != nil {
+			return cleanup(err)
+		}
+		record := encodeRecord(opPut, key, val)
+		if _, err = tmp.WriteAt(record, offset); err != nil {
+			return cleanup(err)
+		}
+		offset += int64(len(record))
+	}
+	if err = tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return before, before, err
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return before, before, err
+	}
+	atomicfile.SyncDir(filepath.Dir(s.path))
+
+	_ = s.f.Close()
+	if err = s.open(); err != nil {
+		s.closed = true
+		_ = s.lock.Close()
+		return before, before, err
+	}
+	s.dirty = false
+	return before, s.size, nil
+}
+
+func (s *Store) Close() error {
+	s.stopOnce.Do(func() { close(s.done) })
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	err := s.sync()
+	s.closed = true
+	return errors.Join(err, s.f.Close(), s.lock.Close())
+}
+
+func (s *Store) syncLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = s.Sync()
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package kvstore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTemp(t *testing.T, opts ...Option) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data", "pokedex.db")
+	s, err := Open(path, opts...)
+	if err != nil {
+		t.Fatalf("expected store, got %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s, path
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy SyncPolicy
+	}{
+		{name: "sync always", policy: SyncAlways},
+		{name: "sync interval", policy: SyncInterval},
+		{name: "sync never", policy: SyncNever},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s, path := openTemp(t, WithSyncPolicy(tc.policy))
+
+			if err := s.Put("pokemon/mew", []byte("psychic")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err := s.Put("pokemon/mew", []byte("legendary")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err := s.Put("pokemon/pikachu", []byte("electric")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err := s.Put("area/canalave", []byte("")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err := s.Delete("pokemon/pikachu"); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err := s.Delete("missing"); err != nil {
+				t.Fatalf("expected deleting a missing key to succeed, got %v", err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if _, err := s.Get("pokemon/mew"); !errors.Is(err, ErrClosed) {
+				t.Fatalf("expected ErrClosed, got %v", err)
+			}
+
+			reopened, err := Open(path)
+			if err != nil {
+				t.Fatalf("expected store, got %v", err)
+			}
+			defer reopened.Close()
+			val, err := reopened.Get("pokemon/mew")
+			if err != nil || string(val) != "legendary" {
+				t.Fatalf("expected latest value, got %q, %v", string(val), err)
+			}
+			if _, err = reopened.Get("pokemon/pikachu"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected deleted key to be gone, got %v", err)
+			}
+			if keys := reopened.Keys("pokemon/"); len(keys) != 1 || keys[0] != "pokemon/mew" {
+				t.Fatalf("unexpected keys %v", keys)
+			}
+			if keys := reopened.Keys(""); len(keys) != 2 || keys[0] != "area/canalave" {
+				t.Fatalf("unexpected keys %v", keys)
+			}
+		})
+	}
+}
+
+func TestStoreRecoversTornTail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		damage func(data []byte) []byte
+	}{
+		{name: "truncated record", damage: func(data []byte) []byte { return data[:len(data)-3] }},
+		{name: "truncated header", damage: func(data []byte) []byte { return data[:len(data)-len("electric")-len("pokemon/pikachu")-5] }},
+		{
+			name: "corrupted record",
+			damage: func(data []byte) []byte {
+				data[len(data)-1] ^= 0xff
+				return data
+			},
+		},
+		{name: "garbage appended", damage: func(data []byte) []byte { return append(data, 0xde, 0xad, 0xbe, 0xef, 0x01) }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s, path := openTemp(t, WithSyncPolicy(SyncAlways))
+			if err := s.Put("pokemon/mew", []byte("psychic")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err := s.Put("pokemon/pikachu", []byte("electric")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			goodSize := s.Stats().FileBytes
+			if err := s.Close(); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			damaged := tc.damage(data)
+			if err = os.WriteFile(path, damaged, 0o644); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			reopened, err := Open(path)
+			if err != nil {
+				t.Fatalf("expected torn tail to be recovered, got %v", err)
+			}
+			defer reopened.Close()
+			if val, err := reopened.Get("pokemon/mew"); err != nil || string(val) != "psychic" {
+				t.Fatalf("expected earlier record to survive, got %q, %v", string(val), err)
+			}
+			stats := reopened.Stats()
+			if stats.Recovered == 0 {
+				t.Fatal("expected recovered bytes to be reported")
+			}
+			if stats.FileBytes > goodSize {
+				t.Fatalf("expected file to be truncated to at most %d bytes, got %d", goodSize, stats.FileBytes)
+			}
+
+			if err = reopened.Put("pokemon/psyduck", []byte("water")); err != nil {
+				t.Fatalf("expected writes after recovery to succeed, got %v", err)
+			}
+			if err = reopened.Close(); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			again, err := Open(path)
+			if err != nil {
+				t.Fatalf("expected store, got %v", err)
+			}
+			defer again.Close()
+			if val, err := again.Get("pokemon/psyduck"); err != nil || string(val) != "water" {
+				t.Fatalf("expected record written after recovery, got %q, %v", string(val), err)
+			}
+			if again.Stats().Recovered != 0 {
+				t.Fatal("expected a clean log on the second reopen")
+			}
+		})
+	}
+}
+
+func TestStoreRefusesCorruptionBeforeValidRecords(t *testing.T) {
+	t.Parallel()
+	s, path := openTemp(t, WithSyncPolicy(SyncAlways))
+	for _, key := range []string{"pokemon/mew", "pokemon/pikachu", "pokemon/psyduck"} {
+		if err := s.Put(key, []byte("data")); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	data[headerSize] ^= 0xff
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if _, err = Open(path); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !bytes.Equal(after, data) {
+		t.Fatalf("expected the log to be left untouched, got %d bytes instead of %d", len(after), len(data))
+	}
+}
+
+func TestStoreLocksAgainstSecondOpen(t *testing.T) {
+	t.Parallel()
+	s, path := openTemp(t)
+
+	if _, err := Open(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked while the store is open, got %v", err)
+	}
+	if _, _, err := s.Compact(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err := Open(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected the lock to survive compaction, got %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("expected the store to open once closed, got %v", err)
+	}
+	_ = reopened.Close()
+}
+
+func TestStoreCompact(t *testing.T) {
+	t.Parallel()
+	s, path := openTemp(t)
+
+	for i := 0; i < 10; i++ {
+		if err := s.Put("pokemon/mew", bytes.Repeat([]byte{byte('a' + i)}, 100)); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+	if err := s.Put("pokemon/pikachu", []byte("electric")); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := s.Put("pokemon/psyduck", []byte("water")); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := s.Delete("pokemon/psyduck"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	live := s.Stats().LiveBytes
+	before, after, err := s.Compact()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if after >= before || after != live {
+		t.Fatalf("expected compaction to shrink %d bytes to %d live bytes, got %d", before, live, after)
+	}
+	if _, err = os.Stat(path + ".compact"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected temporary file to be gone, got %v", err)
+	}
+
+	if err = s.Put("pokemon/wooper", []byte("water")); err != nil {
+		t.Fatalf("expected writes after compaction to succeed, got %v", err)
+	}
+	if err = s.Close(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("expected store, got %v", err)
+	}
+	defer reopened.Close()
+	want := map[string]string{
+		"pokemon/mew":     string(bytes.Repeat([]byte{'j'}, 100)),
+		"pokemon/pikachu": "electric",
+		"pokemon/wooper":  "water",
+	}
+	if keys := reopened.Keys(""); len(keys) != len(want) {
+		t.Fatalf("expected %d keys, got %v", len(want), keys)
+	}
+	for key, val := range want {
+		got, err := reopened.Get(key)
+		if err != nil || string(got) != val {
+			t.Fatalf("expected %s=%q, got %q, %v", key, val, string(got), err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Flarenzy/Pokedex/internal/atomicfile"
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+)
+
+// blobStore is where a DiskCache keeps its encoded entries. idFor maps a
+// cache key to the id the blob is stored under.
+type blobStore interface {
+	idFor(key string) string
+	get(id string) ([]byte, error)
+	put(id string, data []byte) error
+	remove(id string) error
+	ids() ([]string, error)
+}
+
+// fileBlobs stores each blob as a file named after the SHA-256 of its key.
+type fileBlobs struct {
+	dir string
+}
+
+func newFileBlobs(dir string) (fileBlobs, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fileBlobs{}, err
+	}
+	return fileBlobs{dir: dir}, nil
+}
+
+func (f fileBlobs) idFor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+func (f fileBlobs) get(id string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(f.dir, id))
+}
+
+func (f fileBlobs) put(id string, data []byte) error {
+	return atomicfile.Write(filepath.Join(f.dir, id), data)
+}
+
+func (f fileBlobs) remove(id string) error {
+	return os.Remove(filepath.Join(f.dir, id))
+}
+
+func (f fileBlobs) ids() ([]string, error) {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
+			ids = append(ids, file.Name())
+		}
+	}
+	return ids, nil
+}
+
+const storeCachePrefix = "cache/"
+
+type storeBlobs struct {
+	store *kvstore.Store
+}
+
+func (s storeBlobs) idFor(key string) string {
+	return storeCachePrefix + key
+}
+
+func (s storeBlobs) get(id string) ([]byte, error) {
+	return s.store.Get(id)
+}
+
+func (s storeBlobs) put(id string, data []byte) error {
+	return s.store.Put(id, data)
+}
+
+func (s storeBlobs) remove(id string) error {
+	return s.store.Delete(id)
+}
+
+func (s storeBlobs) ids() ([]string, error) {
+	return s.store.Keys(storeCachePrefix), nil
+}
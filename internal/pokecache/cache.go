@@ -1,11 +1,17 @@
 package pokecache
 
 import (
+	"container/heap"
+	"container/list"
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
 )
 
 var (
@@ -13,24 +19,41 @@ var (
 	ErrKeyExists   = errors.New("key already exists")
 )
 
+// Expired entries that carry validators are kept for staleFactor intervals
+// so they can be revalidated; everything else is reaped once it expires.
+const staleFactor = 10
+
 type cacheEntry struct {
-	createdAt time.Time
-	val       []byte
+	key        string
+	createdAt  time.Time
+	ttl        time.Duration
+	val        []byte
+	size       int
+	compressed bool
+	validators domain.Validators
+	element    *list.Element
+	heapIndex  int
 }
 
 type Cache struct {
-	Cached   map[string]cacheEntry
-	interval time.Duration
-	mu       sync.RWMutex
-	done     chan bool
+	entries   map[string]*cacheEntry
+	lru       *list.List
+	reapQueue reapQueue
+	bytes     int64
+	rawBytes  int64
+	opts      options
+	mu        sync.Mutex
+	done      chan bool
+	counters  counters
 }
 
-func NewCache(duration time.Duration) *Cache {
+func NewCache(duration time.Duration, opts ...Option) *Cache {
 	c := &Cache{
-		Cached:   make(map[string]cacheEntry),
-		mu:       sync.RWMutex{},
-		interval: duration,
-		done:     make(chan bool),
+		entries: make(map[string]*cacheEntry),
+		lru:     list.New(),
+		mu:      sync.Mutex{},
+		opts:    newOptions(duration, opts),
+		done:    make(chan bool),
 	}
 
 	ticker := time.NewTicker(duration)
@@ -38,51 +61,206 @@ func NewCache(duration time.Duration) *Cache {
 	return c
 }
 
+func (e *cacheEntry) expired(now time.Time) bool {
+	return expiredAt(e.createdAt, e.ttl, now)
+}
+
+func (e *cacheEntry) reapAt() time.Time {
+	return reapAt(e.createdAt, e.ttl, !e.validators.Empty())
+}
+
+func (e *cacheEntry) body() ([]byte, error) {
+	if !e.compressed {
+		return e.val, nil
+	}
+	return decompress(e.val)
+}
+
 func (c *Cache) Get(key string) ([]byte, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	entry, ok := c.Cached[key]
-	if !ok {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(time.Now()) {
 		//slog.Error("Key not found: ", key)
+		c.counters.misses.Add(1)
 		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 	}
-	return entry.val, nil
+	val, err := entry.body()
+	if err != nil {
+		c.counters.misses.Add(1)
+		return nil, err
+	}
+	c.lru.MoveToFront(entry.element)
+	c.counters.hits.Add(1)
+	return val, nil
 }
 
 func (c *Cache) Add(key string, val []byte) error {
+	return c.AddWithValidators(key, val, domain.Validators{})
+}
+
+func (c *Cache) AddWithValidators(key string, val []byte, v domain.Validators) error {
+	stored, compressed := compress(val, c.opts.compressMinSize)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	slog.Debug("Adding key: ", key, string(val))
-	if _, ok := c.Cached[key]; ok {
-		//slog.Debug("Key already exists: ", key)
-		return fmt.Errorf("%w: %s", ErrKeyExists, key)
+	if entry, ok := c.entries[key]; ok {
+		if !entry.expired(time.Now()) {
+			//slog.Debug("Key already exists: ", key)
+			return fmt.Errorf("%w: %s", ErrKeyExists, key)
+		}
+		c.remove(entry)
+	}
+	if c.opts.maxBytes > 0 && int64(len(stored)) > c.opts.maxBytes {
+		c.counters.evictedCapacity.Add(1)
+		return nil
 	}
-	c.Cached[key] = cacheEntry{
-		createdAt: time.Now(),
-		val:       val,
+	entry := &cacheEntry{
+		key:        key,
+		createdAt:  time.Now(),
+		ttl:        c.opts.ttlFor(key),
+		val:        stored,
+		size:       len(val),
+		compressed: compressed,
+		validators: v,
 	}
+	entry.element = c.lru.PushFront(entry)
+	heap.Push(&c.reapQueue, entry)
+	c.entries[key] = entry
+	c.bytes += int64(len(stored))
+	c.rawBytes += int64(len(val))
+	c.evictOverCapacity()
 	//slog.Info("Added key: ", "key", key)
 	return nil
 }
 
+func (c *Cache) GetStale(key string) ([]byte, domain.Validators, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, domain.Validators{}, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	val, err := entry.body()
+	if err != nil {
+		return nil, domain.Validators{}, err
+	}
+	return val, entry.validators, nil
+}
+
+func (c *Cache) Refresh(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	entry.createdAt = time.Now()
+	heap.Fix(&c.reapQueue, entry.heapIndex)
+	c.lru.MoveToFront(entry.element)
+	c.counters.revalidations.Add(1)
+	return nil
+}
+
+func (c *Cache) List(prefix string) ([]domain.CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var entries []domain.CacheEntry
+	for key, entry := range c.entries {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		entries = append(entries, domain.CacheEntry{
+			Key:       key,
+			Size:      entry.size,
+			CreatedAt: entry.createdAt,
+			ExpiresAt: expiresAt(entry.createdAt, entry.ttl),
+		})
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func (c *Cache) Purge(prefix string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := 0
+	for key, entry := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(entry)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*cacheEntry)
+	c.lru.Init()
+	c.reapQueue.entries = nil
+	c.bytes = 0
+	c.rawBytes = 0
+	return nil
+}
+
+func (c *Cache) Stats() domain.CacheStats {
+	stats := c.counters.stats()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats.StoredBytes = uint64(c.bytes)
+	stats.UncompressedBytes = uint64(c.rawBytes)
+	return stats
+}
+
 func (c *Cache) Done() {
 	c.done <- true
 	close(c.done)
 }
 
+func (c *Cache) remove(entry *cacheEntry) {
+	c.lru.Remove(entry.element)
+	heap.Remove(&c.reapQueue, entry.heapIndex)
+	delete(c.entries, entry.key)
+	c.bytes -= int64(len(entry.val))
+	c.rawBytes -= int64(entry.size)
+}
+
+func (c *Cache) evictOverCapacity() {
+	for (c.opts.maxEntries > 0 && len(c.entries) > c.opts.maxEntries) || (c.opts.maxBytes > 0 && c.bytes > c.opts.maxBytes) {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			return
+		}
+		c.remove(oldest.Value.(*cacheEntry))
+		c.counters.evictedCapacity.Add(1)
+	}
+}
+
+// reap pops entries off the reap queue until it reaches one that isn't due,
+// so a tick only touches the entries it removes.
+func (c *Cache) reap(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.reapQueue.Len() > 0 {
+		next := c.reapQueue.entries[0]
+		due := next.reapAt()
+		if due.IsZero() || !due.Before(now) {
+			return
+		}
+		c.remove(next)
+		c.counters.evictedTTL.Add(1)
+		//slog.Info("Cache reaped: ", k)
+	}
+}
+
 func (c *Cache) reapLoop(ticker *time.Ticker) {
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
-				for k, v := range c.Cached {
-					c.mu.Lock()
-					if v.createdAt.Add(c.interval).Before(time.Now()) {
-						delete(c.Cached, k)
-						//slog.Info("Cache reaped: ", k)
-					}
-					c.mu.Unlock()
-				}
+				c.reap(time.Now())
 			case <-c.done:
 				ticker.Stop()
 				//slog.Info("Closing cache...")
@@ -92,3 +270,44 @@ func (c *Cache) reapLoop(ticker *time.Ticker) {
 	}()
 
 }
+
+// reapQueue is a min-heap of entries ordered by the time they become
+// reapable. Entries that never expire sort last.
+type reapQueue struct {
+	entries []*cacheEntry
+}
+
+func (q *reapQueue) Len() int { return len(q.entries) }
+
+func (q *reapQueue) Less(i, j int) bool {
+	a, b := q.entries[i].reapAt(), q.entries[j].reapAt()
+	if a.IsZero() || b.IsZero() {
+		return b.IsZero() && !a.IsZero()
+	}
+	return a.Before(b)
+}
+
+func (q *reapQueue) Swap(i, j int) {
+	q.entries[i], q.entries[j] = q.entries[j], q.entries[i]
+	q.entries[i].heapIndex = i
+	q.entries[j].heapIndex = j
+}
+
+func (q *reapQueue) Push(x any) {
+	entry := x.(*cacheEntry)
+	entry.heapIndex = len(q.entries)
+	q.entries = append(q.entries, entry)
+}
+
+func (q *reapQueue) Pop() any {
+	last := len(q.entries) - 1
+	entry := q.entries[last]
+	q.entries[last] = nil
+	q.entries = q.entries[:last]
+	entry.heapIndex = -1
+	return entry
+}
+
+func sortEntries(entries []domain.CacheEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+}
@@ -2,10 +2,13 @@ package pokecache
 
 import (
 	"bytes"
+	"container/heap"
 	"errors"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
 )
 
 const first_twenty_resp = `{"count":1089,"next":"https://pokeapi.co/api/v2/location-area/?offset=20&limit=20","previous":null,"results":[{"name":"canalave-city-area","url":"https://pokeapi.co/api/v2/location-area/1/"},{"name":"eterna-city-area","url":"https://pokeapi.co/api/v2/location-area/2/"},{"name":"pastoria-city-area","url":"https://pokeapi.co/api/v2/location-area/3/"},{"name":"sunyshore-city-area","url":"https://pokeapi.co/api/v2/location-area/4/"},{"name":"sinnoh-pokemon-league-area","url":"https://pokeapi.co/api/v2/location-area/5/"},{"name":"oreburgh-mine-1f","url":"https://pokeapi.co/api/v2/location-area/6/"},{"name":"oreburgh-mine-b1f","url":"https://pokeapi.co/api/v2/location-area/7/"},{"name":"valley-windworks-area","url":"https://pokeapi.co/api/v2/location-area/8/"},{"name":"eterna-forest-area","url":"https://pokeapi.co/api/v2/location-area/9/"},{"name":"fuego-ironworks-area","url":"https://pokeapi.co/api/v2/location-area/10/"},{"name":"mt-coronet-1f-route-207","url":"https://pokeapi.co/api/v2/location-area/11/"},{"name":"mt-coronet-2f","url":"https://pokeapi.co/api/v2/location-area/12/"},{"name":"mt-coronet-3f","url":"https://pokeapi.co/api/v2/location-area/13/"},{"name":"mt-coronet-exterior-snowfall","url":"https://pokeapi.co/api/v2/location-area/14/"},{"name":"mt-coronet-exterior-blizzard","url":"https://pokeapi.co/api/v2/location-area/15/"},{"name":"mt-coronet-4f","url":"https://pokeapi.co/api/v2/location-area/16/"},{"name":"mt-coronet-4f-small-room","url":"https://pokeapi.co/api/v2/location-area/17/"},{"name":"mt-coronet-5f","url":"https://pokeapi.co/api/v2/location-area/18/"},{"name":"mt-coronet-6f","url":"https://pokeapi.co/api/v2/location-area/19/"},{"name":"mt-coronet-1f-from-exterior","url":"https://pokeapi.co/api/v2/location-area/20/"}]}"`
@@ -132,3 +135,181 @@ func TestReapLoop(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestCacheRevalidation(t *testing.T) {
+	t.Parallel()
+	cache := NewCache(100 * time.Millisecond)
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/"
+	v := domain.Validators{ETag: `"abc"`, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+	if err := cache.AddWithValidators(key, []byte(first_twenty_resp), v); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	if _, err := cache.Get(key); err != nil {
+		t.Fatalf("expected fresh hit, got %v", err)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if _, err := cache.Get(key); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected expired entry to miss, got %v", err)
+	}
+	val, got, err := cache.GetStale(key)
+	if err != nil {
+		t.Fatalf("expected entry with validators to survive reaping, got %v", err)
+	}
+	if got != v || !bytes.Equal(val, []byte(first_twenty_resp)) {
+		t.Fatalf("unexpected stale entry %v %q", got, string(val))
+	}
+
+	if err = cache.Refresh(key); err != nil {
+		t.Fatalf("expected refresh to succeed, got %v", err)
+	}
+	if _, err = cache.Get(key); err != nil {
+		t.Fatalf("expected refreshed entry to hit, got %v", err)
+	}
+	if err = cache.Refresh("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	stats := cache.Stats()
+	want := domain.CacheStats{
+		Hits:              2,
+		Misses:            1,
+		Revalidations:     1,
+		StoredBytes:       stats.StoredBytes,
+		UncompressedBytes: uint64(len(first_twenty_resp)),
+	}
+	if stats != want || stats.StoredBytes >= stats.UncompressedBytes {
+		t.Fatalf("expected stats %+v, got %+v", want, stats)
+	}
+}
+
+func TestCacheAddReplacesExpiredEntry(t *testing.T) {
+	t.Parallel()
+	cache := NewCache(time.Hour)
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/"
+	if err := cache.Add(key, []byte("old")); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	cache.entries[key].createdAt = time.Now().Add(-2 * time.Hour)
+	if err := cache.Add(key, []byte("new")); err != nil {
+		t.Fatalf("expected expired key to be replaced, got %v", err)
+	}
+	val, err := cache.Get(key)
+	if err != nil || string(val) != "new" {
+		t.Fatalf("expected new value, got %q, %v", string(val), err)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantEvicted []string
+		wantKept    []string
+	}{
+		{
+			name:        "max entries",
+			opts:        []Option{WithMaxEntries(2)},
+			wantEvicted: []string{"b"},
+			wantKept:    []string{"a", "c"},
+		},
+		{
+			name:        "max bytes",
+			opts:        []Option{WithMaxBytes(10)},
+			wantEvicted: []string{"b"},
+			wantKept:    []string{"a", "c"},
+		},
+		{
+			name:        "no limits",
+			wantEvicted: []string{},
+			wantKept:    []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cache := NewCache(time.Hour, tc.opts...)
+			defer cache.Done()
+
+			for _, key := range []string{"a", "b"} {
+				if err := cache.Add(key, []byte("12345")); err != nil {
+					t.Fatalf("error adding key %s: %v", key, err)
+				}
+			}
+			if _, err := cache.Get("a"); err != nil {
+				t.Fatalf("expected hit for a, got %v", err)
+			}
+			if err := cache.Add("c", []byte("12345")); err != nil {
+				t.Fatalf("error adding key c: %v", err)
+			}
+
+			for _, key := range tc.wantEvicted {
+				if _, err := cache.Get(key); !errors.Is(err, ErrKeyNotFound) {
+					t.Fatalf("expected %s to be evicted, got %v", key, err)
+				}
+			}
+			for _, key := range tc.wantKept {
+				if _, err := cache.Get(key); err != nil {
+					t.Fatalf("expected %s to be kept, got %v", key, err)
+				}
+			}
+			if got := cache.Stats().EvictedCapacity; got != uint64(len(tc.wantEvicted)) {
+				t.Fatalf("expected %d capacity evictions, got %d", len(tc.wantEvicted), got)
+			}
+		})
+	}
+}
+
+func TestCacheSkipsValuesLargerThanMaxBytes(t *testing.T) {
+	t.Parallel()
+	cache := NewCache(time.Hour, WithMaxBytes(4))
+	defer cache.Done()
+
+	if err := cache.Add("small", []byte("1234")); err != nil {
+		t.Fatalf("error adding key small: %v", err)
+	}
+	if err := cache.Add("big", []byte("12345")); err != nil {
+		t.Fatalf("expected oversized value to be skipped, got %v", err)
+	}
+	if _, err := cache.Get("big"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected oversized value not to be cached, got %v", err)
+	}
+	if _, err := cache.Get("small"); err != nil {
+		t.Fatalf("expected small value to be kept, got %v", err)
+	}
+}
+
+func TestCacheReapCountsTTLEvictions(t *testing.T) {
+	t.Parallel()
+	cache := NewCache(time.Hour)
+	defer cache.Done()
+
+	for _, key := range []string{"old", "new"} {
+		if err := cache.Add(key, []byte(key)); err != nil {
+			t.Fatalf("error adding key %s: %v", key, err)
+		}
+	}
+	cache.mu.Lock()
+	cache.entries["old"].createdAt = time.Now().Add(-2 * time.Hour)
+	heap.Fix(&cache.reapQueue, cache.entries["old"].heapIndex)
+	cache.mu.Unlock()
+
+	cache.reap(time.Now())
+
+	if _, _, err := cache.GetStale("old"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected old entry to be reaped, got %v", err)
+	}
+	if _, err := cache.Get("new"); err != nil {
+		t.Fatalf("expected new entry to be kept, got %v", err)
+	}
+	if got := cache.Stats().EvictedTTL; got != 1 {
+		t.Fatalf("expected 1 TTL eviction, got %d", got)
+	}
+}
@@ -0,0 +1,40 @@
+package pokecache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// defaultCompressMinSize is the smallest body worth compressing; below it the
+// gzip header costs more than it saves.
+const defaultCompressMinSize = 1024
+
+// compress gzips val when it is at least minSize bytes and the result is
+// actually smaller. It reports whether the returned bytes are compressed.
+func compress(val []byte, minSize int) ([]byte, bool) {
+	if minSize < 0 || len(val) < minSize {
+		return val, false
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(val); err != nil {
+		return val, false
+	}
+	if err := zw.Close(); err != nil {
+		return val, false
+	}
+	if buf.Len() >= len(val) {
+		return val, false
+	}
+	return buf.Bytes(), true
+}
+
+func decompress(val []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(val))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,69 @@
+package pokecache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheCompression(t *testing.T) {
+	t.Parallel()
+
+	large := []byte(strings.Repeat(`{"name":"front_default","url":"https://pokeapi.co/media/sprites/pokemon/151.png"}`, 64))
+	tests := []struct {
+		name           string
+		opts           []Option
+		val            []byte
+		wantCompressed bool
+	}{
+		{name: "large body", val: large, wantCompressed: true},
+		{name: "small body", val: []byte(`{"name":"mew"}`)},
+		{name: "compression disabled", opts: []Option{WithCompressionMinSize(-1)}, val: large},
+		{name: "incompressible body", opts: []Option{WithCompressionMinSize(1)}, val: []byte("ab")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cache := NewCache(time.Hour, tc.opts...)
+			defer cache.Done()
+
+			key := "https://pokeapi.co/api/v2/pokemon/mew"
+			if err := cache.Add(key, tc.val); err != nil {
+				t.Fatalf("error adding key %s: %v", key, err)
+			}
+			if got := cache.entries[key].compressed; got != tc.wantCompressed {
+				t.Fatalf("expected compressed=%v, got %v", tc.wantCompressed, got)
+			}
+
+			val, err := cache.Get(key)
+			if err != nil || !bytes.Equal(val, tc.val) {
+				t.Fatalf("expected original bytes from Get, got %d bytes, %v", len(val), err)
+			}
+			stale, _, err := cache.GetStale(key)
+			if err != nil || !bytes.Equal(stale, tc.val) {
+				t.Fatalf("expected original bytes from GetStale, got %d bytes, %v", len(stale), err)
+			}
+			entries, err := cache.List("")
+			if err != nil || len(entries) != 1 || entries[0].Size != len(tc.val) {
+				t.Fatalf("expected List to report the original size, got %+v, %v", entries, err)
+			}
+
+			stats := cache.Stats()
+			if stats.UncompressedBytes != uint64(len(tc.val)) {
+				t.Fatalf("expected %d uncompressed bytes, got %d", len(tc.val), stats.UncompressedBytes)
+			}
+			if tc.wantCompressed != (stats.StoredBytes < stats.UncompressedBytes) {
+				t.Fatalf("unexpected stored bytes %d for %d uncompressed", stats.StoredBytes, stats.UncompressedBytes)
+			}
+
+			if _, err = cache.Purge(""); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if stats = cache.Stats(); stats.StoredBytes != 0 || stats.UncompressedBytes != 0 {
+				t.Fatalf("expected sizes to drop to zero, got %+v", stats)
+			}
+		})
+	}
+}
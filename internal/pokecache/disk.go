@@ -0,0 +1,245 @@
+package pokecache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+)
+
+type diskEntry struct {
+	Key          string    `json:"key"`
+	CreatedAt    time.Time `json:"created_at"`
+	Val          []byte    `json:"val"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+}
+
+func (e diskEntry) validators() domain.Validators {
+	return domain.Validators{ETag: e.ETag, LastModified: e.LastModified}
+}
+
+// DiskCache keeps entries as JSON blobs that survive restarts, either one
+// file per entry (NewDiskCache) or records in a kvstore.Store
+// (NewStoreCache). Entries expire according to the same TTL policy the
+// in-memory Cache uses and are removed by a periodic reap loop. The size
+// limits in Option are not applied on disk.
+type DiskCache struct {
+	blobs    blobStore
+	opts     options
+	mu       sync.RWMutex
+	done     chan bool
+	counters counters
+}
+
+func NewDiskCache(dir string, duration time.Duration, opts ...Option) (*DiskCache, error) {
+	blobs, err := newFileBlobs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newDiskCache(blobs, duration, opts), nil
+}
+
+// NewStoreCache keeps entries in store under the "cache/" prefix. The store
+// is owned by the caller and is not closed by Done.
+func NewStoreCache(store *kvstore.Store, duration time.Duration, opts ...Option) *DiskCache {
+	return newDiskCache(storeBlobs{store: store}, duration, opts)
+}
+
+func newDiskCache(blobs blobStore, duration time.Duration, opts []Option) *DiskCache {
+	c := &DiskCache{
+		blobs: blobs,
+		opts:  newOptions(duration, opts),
+		done:  make(chan bool),
+	}
+	ticker := time.NewTicker(duration)
+	c.reapLoop(ticker)
+	return c
+}
+
+func (c *DiskCache) expired(entry diskEntry, now time.Time) bool {
+	return expiredAt(entry.CreatedAt, c.opts.ttlFor(entry.Key), now)
+}
+
+func (c *DiskCache) reapable(entry diskEntry, now time.Time) bool {
+	due := reapAt(entry.CreatedAt, c.opts.ttlFor(entry.Key), !entry.validators().Empty())
+	return !due.IsZero() && due.Before(now)
+}
+
+func (c *DiskCache) read(id string) (diskEntry, error) {
+	var entry diskEntry
+	data, err := c.blobs.get(id)
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
+
+func (c *DiskCache) Get(key string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, err := c.read(c.blobs.idFor(key))
+	if err != nil || entry.Key != key || c.expired(entry, time.Now()) {
+		c.counters.misses.Add(1)
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	c.counters.hits.Add(1)
+	return entry.Val, nil
+}
+
+func (c *DiskCache) Add(key string, val []byte) error {
+	return c.AddWithValidators(key, val, domain.Validators{})
+}
+
+func (c *DiskCache) AddWithValidators(key string, val []byte, v domain.Validators) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.blobs.idFor(key)
+	if entry, err := c.read(id); err == nil && entry.Key == key && !c.expired(entry, time.Now()) {
+		return fmt.Errorf("%w: %s", ErrKeyExists, key)
+	}
+	return c.write(id, diskEntry{
+		Key:          key,
+		CreatedAt:    time.Now(),
+		Val:          val,
+		ETag:         v.ETag,
+		LastModified: v.LastModified,
+	})
+}
+
+func (c *DiskCache) write(id string, entry diskEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return c.blobs.put(id, data)
+}
+
+func (c *DiskCache) GetStale(key string) ([]byte, domain.Validators, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, err := c.read(c.blobs.idFor(key))
+	if err != nil || entry.Key != key {
+		return nil, domain.Validators{}, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	return entry.Val, entry.validators(), nil
+}
+
+func (c *DiskCache) Refresh(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.blobs.idFor(key)
+	entry, err := c.read(id)
+	if err != nil || entry.Key != key {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	entry.CreatedAt = time.Now()
+	if err = c.write(id, entry); err != nil {
+		return err
+	}
+	c.counters.revalidations.Add(1)
+	return nil
+}
+
+// entries calls fn for every stored entry. Unreadable blobs are passed with
+// a non-nil error so callers can decide whether to drop them.
+func (c *DiskCache) entries(fn func(id string, entry diskEntry, err error)) error {
+	ids, err := c.blobs.ids()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		entry, err := c.read(id)
+		fn(id, entry, err)
+	}
+	return nil
+}
+
+func (c *DiskCache) List(prefix string) ([]domain.CacheEntry, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var entries []domain.CacheEntry
+	err := c.entries(func(id string, entry diskEntry, err error) {
+		if err != nil || !strings.HasPrefix(entry.Key, prefix) {
+			return
+		}
+		entries = append(entries, domain.CacheEntry{
+			Key:       entry.Key,
+			Size:      len(entry.Val),
+			CreatedAt: entry.CreatedAt,
+			ExpiresAt: expiresAt(entry.CreatedAt, c.opts.ttlFor(entry.Key)),
+		})
+	})
+	sortEntries(entries)
+	return entries, err
+}
+
+func (c *DiskCache) Purge(prefix string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := 0
+	var errs []error
+	err := c.entries(func(id string, entry diskEntry, err error) {
+		if err != nil || !strings.HasPrefix(entry.Key, prefix) {
+			return
+		}
+		if err = c.blobs.remove(id); err != nil {
+			errs = append(errs, err)
+			return
+		}
+		removed++
+	})
+	return removed, errors.Join(append(errs, err)...)
+}
+
+func (c *DiskCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	err := c.entries(func(id string, _ diskEntry, _ error) {
+		if err := c.blobs.remove(id); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+func (c *DiskCache) Stats() domain.CacheStats {
+	return c.counters.stats()
+}
+
+func (c *DiskCache) Done() {
+	c.done <- true
+	close(c.done)
+}
+
+func (c *DiskCache) reap() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	_ = c.entries(func(id string, entry diskEntry, err error) {
+		if err != nil || c.reapable(entry, now) {
+			_ = c.blobs.remove(id)
+		}
+	})
+}
+
+func (c *DiskCache) reapLoop(ticker *time.Ticker) {
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				c.reap()
+			case <-c.done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+package pokecache
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+)
+
+func TestDiskCache(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 20*time.Second)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/"
+	if err = cache.Add(key, []byte(first_twenty_resp)); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	if err = cache.Add(key, []byte(first_twenty_resp)); !errors.Is(err, ErrKeyExists) {
+		t.Fatalf("expected ErrKeyExists, got %v", err)
+	}
+
+	reopened, err := NewDiskCache(dir, 20*time.Second)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	defer reopened.Done()
+	val, err := reopened.Get(key)
+	if err != nil {
+		t.Fatalf("expected value after reopen, got %v", err)
+	}
+	if !bytes.Equal(val, []byte(first_twenty_resp)) {
+		t.Fatalf("expected %q, got %q", first_twenty_resp, string(val))
+	}
+
+	_, err = reopened.Get("missing")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestDiskCacheExpiry(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/?offset=20&limit=20"
+	if err = cache.Add(key, []byte(second_twenty_resp)); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, err = cache.Get(key); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected expired entry to be missing, got %v", err)
+	}
+	if err = cache.Add(key, []byte(second_twenty_resp)); err != nil {
+		t.Fatalf("expected expired key to be replaceable, got %v", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir failed: %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected reap loop to remove expired files, found %d", len(files))
+	}
+}
+
+func TestDiskCacheReapable(t *testing.T) {
+	t.Parallel()
+	cache, err := NewDiskCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/canalave-city-area"
+	now := time.Now()
+	tests := []struct {
+		name  string
+		entry diskEntry
+		want  bool
+	}{
+		{name: "fresh", entry: diskEntry{Key: key, CreatedAt: now}, want: false},
+		{name: "expired", entry: diskEntry{Key: key, CreatedAt: now.Add(-2 * time.Hour)}, want: true},
+		{name: "expired with etag", entry: diskEntry{Key: key, CreatedAt: now.Add(-2 * time.Hour), ETag: `"abc"`}, want: false},
+		{name: "stale window over", entry: diskEntry{Key: key, CreatedAt: now.Add(-(staleFactor + 1) * time.Hour), ETag: `"abc"`}, want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cache.reapable(tc.entry, now); got != tc.want {
+				t.Fatalf("expected reapable %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStoreCachePersistsAcrossReopen(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	store, err := kvstore.Open(path)
+	if err != nil {
+		t.Fatalf("expected store, got %v", err)
+	}
+	cache := NewStoreCache(store, time.Hour)
+
+	key := "https://pokeapi.co/api/v2/location-area/"
+	v := domain.Validators{ETag: `"abc"`}
+	if err = cache.AddWithValidators(key, []byte(first_twenty_resp), v); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	cache.Done()
+	if err = store.Close(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	reopened, err := kvstore.Open(path)
+	if err != nil {
+		t.Fatalf("expected store, got %v", err)
+	}
+	defer reopened.Close()
+	cache = NewStoreCache(reopened, time.Hour)
+	defer cache.Done()
+	val, got, err := cache.GetStale(key)
+	if err != nil || got != v || !bytes.Equal(val, []byte(first_twenty_resp)) {
+		t.Fatalf("expected entry and validators after reopen, got %v %q, %v", got, string(val), err)
+	}
+	if keys := reopened.Keys(""); len(keys) != 1 || keys[0] != "cache/"+key {
+		t.Fatalf("expected entry under the cache prefix, got %v", keys)
+	}
+}
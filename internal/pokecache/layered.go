@@ -0,0 +1,179 @@
+package pokecache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+)
+
+// Layered chains caches from fastest to slowest. A hit in a slower layer is
+// copied into every faster layer so the next Get is served from the top.
+type Layered struct {
+	layers   []domain.Cacher
+	counters counters
+}
+
+func NewLayered(layers ...domain.Cacher) *Layered {
+	return &Layered{layers: layers}
+}
+
+func (l *Layered) Get(key string) ([]byte, error) {
+	for i, layer := range l.layers {
+		val, err := layer.Get(key)
+		if err != nil {
+			continue
+		}
+		if i > 0 {
+			var v domain.Validators
+			if r, ok := layer.(domain.Revalidator); ok {
+				_, v, _ = r.GetStale(key)
+			}
+			for _, faster := range l.layers[:i] {
+				_ = addWithValidators(faster, key, val, v)
+			}
+		}
+		l.counters.hits.Add(1)
+		return val, nil
+	}
+	l.counters.misses.Add(1)
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+}
+
+func (l *Layered) Add(key string, val []byte) error {
+	return l.AddWithValidators(key, val, domain.Validators{})
+}
+
+func (l *Layered) AddWithValidators(key string, val []byte, v domain.Validators) error {
+	var errs []error
+	existing := 0
+	for _, layer := range l.layers {
+		err := addWithValidators(layer, key, val, v)
+		if errors.Is(err, ErrKeyExists) {
+			existing++
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if existing == len(l.layers) {
+		return fmt.Errorf("%w: %s", ErrKeyExists, key)
+	}
+	return nil
+}
+
+func (l *Layered) GetStale(key string) ([]byte, domain.Validators, error) {
+	for _, layer := range l.layers {
+		r, ok := layer.(domain.Revalidator)
+		if !ok {
+			continue
+		}
+		val, v, err := r.GetStale(key)
+		if err == nil {
+			return val, v, nil
+		}
+	}
+	return nil, domain.Validators{}, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+}
+
+func (l *Layered) Refresh(key string) error {
+	refreshed := false
+	for _, layer := range l.layers {
+		r, ok := layer.(domain.Revalidator)
+		if !ok {
+			continue
+		}
+		err := r.Refresh(key)
+		if err == nil {
+			refreshed = true
+			continue
+		}
+		if !errors.Is(err, ErrKeyNotFound) {
+			return err
+		}
+	}
+	if !refreshed {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	l.counters.revalidations.Add(1)
+	return nil
+}
+
+// List merges the entries of every layer. When a key is held by several
+// layers the fastest one describes it.
+func (l *Layered) List(prefix string) ([]domain.CacheEntry, error) {
+	seen := make(map[string]bool)
+	var entries []domain.CacheEntry
+	var errs []error
+	for _, layer := range l.layers {
+		layerEntries, err := layer.List(prefix)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, entry := range layerEntries {
+			if seen[entry.Key] {
+				continue
+			}
+			seen[entry.Key] = true
+			entries = append(entries, entry)
+		}
+	}
+	sortEntries(entries)
+	return entries, errors.Join(errs...)
+}
+
+// Purge reports the number of distinct keys removed across all layers.
+func (l *Layered) Purge(prefix string) (int, error) {
+	entries, err := l.List(prefix)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, layer := range l.layers {
+		if _, err = layer.Purge(prefix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(entries), errors.Join(errs...)
+}
+
+func (l *Layered) Clear() error {
+	var errs []error
+	for _, layer := range l.layers {
+		errs = append(errs, layer.Clear())
+	}
+	return errors.Join(errs...)
+}
+
+// Stats reports hits and misses for the chain as a whole and sums the
+// evictions and sizes of every layer.
+func (l *Layered) Stats() domain.CacheStats {
+	stats := l.counters.stats()
+	for _, layer := range l.layers {
+		if r, ok := layer.(domain.StatsReporter); ok {
+			layerStats := r.Stats()
+			stats.EvictedTTL += layerStats.EvictedTTL
+			stats.EvictedCapacity += layerStats.EvictedCapacity
+			stats.StoredBytes += layerStats.StoredBytes
+			stats.UncompressedBytes += layerStats.UncompressedBytes
+		}
+	}
+	return stats
+}
+
+func (l *Layered) Done() {
+	for _, layer := range l.layers {
+		layer.Done()
+	}
+}
+
+func addWithValidators(c domain.Cacher, key string, val []byte, v domain.Validators) error {
+	if r, ok := c.(domain.Revalidator); ok {
+		return r.AddWithValidators(key, val, v)
+	}
+	return c.Add(key, val)
+}
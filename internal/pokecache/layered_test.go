@@ -0,0 +1,135 @@
+package pokecache
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+)
+
+func TestLayeredBackfillsFasterLayers(t *testing.T) {
+	t.Parallel()
+	memory := NewCache(20 * time.Second)
+	disk, err := NewDiskCache(t.TempDir(), 20*time.Second)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	cache := NewLayered(memory, disk)
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/"
+	if err = disk.Add(key, []byte(first_twenty_resp)); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	val, err := cache.Get(key)
+	if err != nil {
+		t.Fatalf("expected hit from disk layer, got %v", err)
+	}
+	if !bytes.Equal(val, []byte(first_twenty_resp)) {
+		t.Fatalf("unexpected value %q", string(val))
+	}
+	if _, err = memory.Get(key); err != nil {
+		t.Fatalf("expected memory layer to be backfilled, got %v", err)
+	}
+}
+
+// staleCounter counts GetStale calls on the Cache it wraps.
+type staleCounter struct {
+	*Cache
+	stale atomic.Int32
+}
+
+func (s *staleCounter) GetStale(key string) ([]byte, domain.Validators, error) {
+	s.stale.Add(1)
+	return s.Cache.GetStale(key)
+}
+
+func TestLayeredTopHitSkipsValidators(t *testing.T) {
+	t.Parallel()
+	top := &staleCounter{Cache: NewCache(20 * time.Second)}
+	bottom := &staleCounter{Cache: NewCache(20 * time.Second)}
+	cache := NewLayered(top, bottom)
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/pokemon/mew"
+	if err := bottom.AddWithValidators(key, []byte("mew"), domain.Validators{ETag: `"mew"`}); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Get(key); err != nil {
+			t.Fatalf("expected hit, got %v", err)
+		}
+	}
+	if got := bottom.stale.Load(); got != 1 {
+		t.Fatalf("expected validators to be read once for the backfill, got %d", got)
+	}
+	if got := top.stale.Load(); got != 0 {
+		t.Fatalf("expected top layer hits not to read validators, got %d", got)
+	}
+}
+
+func TestLayeredAdd(t *testing.T) {
+	t.Parallel()
+	memory := NewCache(20 * time.Second)
+	disk, err := NewDiskCache(t.TempDir(), 20*time.Second)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	cache := NewLayered(memory, disk)
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/location-area/"
+	if err = cache.Add(key, []byte(first_twenty_resp)); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	if _, err = disk.Get(key); err != nil {
+		t.Fatalf("expected disk layer to hold key, got %v", err)
+	}
+	if err = cache.Add(key, []byte(first_twenty_resp)); !errors.Is(err, ErrKeyExists) {
+		t.Fatalf("expected ErrKeyExists when every layer has the key, got %v", err)
+	}
+	if _, err = cache.Get("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestLayeredRevalidation(t *testing.T) {
+	t.Parallel()
+	memory := NewCache(20 * time.Second)
+	disk, err := NewDiskCache(t.TempDir(), 20*time.Second)
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	cache := NewLayered(memory, disk)
+	defer cache.Done()
+
+	key := "https://pokeapi.co/api/v2/pokemon/mew"
+	v := domain.Validators{ETag: `"mew"`}
+	if err = disk.AddWithValidators(key, []byte("mew"), v); err != nil {
+		t.Fatalf("error adding key %s: %v", key, err)
+	}
+	if _, err = cache.Get(key); err != nil {
+		t.Fatalf("expected hit, got %v", err)
+	}
+	if _, got, err := memory.GetStale(key); err != nil || got != v {
+		t.Fatalf("expected validators to be backfilled, got %v, %v", got, err)
+	}
+	if _, got, err := cache.GetStale(key); err != nil || got != v {
+		t.Fatalf("expected stale validators, got %v, %v", got, err)
+	}
+	if err = cache.Refresh(key); err != nil {
+		t.Fatalf("expected refresh to succeed, got %v", err)
+	}
+	if err = cache.Refresh("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	_, _ = cache.Get("missing")
+
+	want := domain.CacheStats{Hits: 1, Misses: 1, Revalidations: 1, StoredBytes: 3, UncompressedBytes: 3}
+	if got := cache.Stats(); got != want {
+		t.Fatalf("expected stats %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,105 @@
+package pokecache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+)
+
+func TestCachersListPurgeClear(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		newCache func(t *testing.T) domain.Cacher
+	}{
+		{
+			name: "memory",
+			newCache: func(t *testing.T) domain.Cacher {
+				return NewCache(time.Hour)
+			},
+		},
+		{
+			name: "disk",
+			newCache: func(t *testing.T) domain.Cacher {
+				c, err := NewDiskCache(t.TempDir(), time.Hour)
+				if err != nil {
+					t.Fatalf("expected disk cache, got %v", err)
+				}
+				return c
+			},
+		},
+		{
+			name: "store",
+			newCache: func(t *testing.T) domain.Cacher {
+				store, err := kvstore.Open(filepath.Join(t.TempDir(), "pokedex.db"))
+				if err != nil {
+					t.Fatalf("expected store, got %v", err)
+				}
+				t.Cleanup(func() { _ = store.Close() })
+				return NewStoreCache(store, time.Hour)
+			},
+		},
+		{
+			name: "layered",
+			newCache: func(t *testing.T) domain.Cacher {
+				disk, err := NewDiskCache(t.TempDir(), time.Hour)
+				if err != nil {
+					t.Fatalf("expected disk cache, got %v", err)
+				}
+				return NewLayered(NewCache(time.Hour), disk)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cache := tc.newCache(t)
+			defer cache.Done()
+
+			keys := []string{"b/2", "a/1", "b/1"}
+			for _, key := range keys {
+				if err := cache.Add(key, []byte(key)); err != nil {
+					t.Fatalf("error adding key %s: %v", key, err)
+				}
+			}
+
+			entries, err := cache.List("b/")
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if len(entries) != 2 || entries[0].Key != "b/1" || entries[1].Key != "b/2" {
+				t.Fatalf("expected sorted b/ entries, got %+v", entries)
+			}
+			if entries[0].Size != 3 || entries[0].CreatedAt.IsZero() || entries[0].ExpiresAt.IsZero() {
+				t.Fatalf("expected entry metadata, got %+v", entries[0])
+			}
+
+			removed, err := cache.Purge("b/")
+			if err != nil || removed != 2 {
+				t.Fatalf("expected 2 entries purged, got %d, %v", removed, err)
+			}
+			if _, err = cache.Get("b/1"); err == nil {
+				t.Fatal("expected purged key to miss")
+			}
+			if _, err = cache.Get("a/1"); err != nil {
+				t.Fatalf("expected other key to be kept, got %v", err)
+			}
+
+			if err = cache.Clear(); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			entries, err = cache.List("")
+			if err != nil || len(entries) != 0 {
+				t.Fatalf("expected empty cache, got %+v, %v", entries, err)
+			}
+			if err = cache.Add("a/1", []byte("a/1")); err != nil {
+				t.Fatalf("expected cache to be usable after clear, got %v", err)
+			}
+		})
+	}
+}
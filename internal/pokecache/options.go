@@ -0,0 +1,51 @@
+package pokecache
+
+import "time"
+
+type options struct {
+	maxBytes        int64
+	maxEntries      int
+	compressMinSize int
+	policy          *TTLPolicy
+}
+
+type Option func(*options)
+
+// WithMaxBytes bounds the total size of values held by a Cache. Least
+// recently used entries are evicted to make room.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) { o.maxBytes = n }
+}
+
+// WithMaxEntries bounds the number of entries held by a Cache. Least recently
+// used entries are evicted to make room.
+func WithMaxEntries(n int) Option {
+	return func(o *options) { o.maxEntries = n }
+}
+
+// WithCompressionMinSize gzips values held by a Cache once they reach n
+// bytes. A negative n turns compression off.
+func WithCompressionMinSize(n int) Option {
+	return func(o *options) { o.compressMinSize = n }
+}
+
+// WithTTLPolicy gives each key its own lifetime instead of the cache-wide
+// duration.
+func WithTTLPolicy(p TTLPolicy) Option {
+	return func(o *options) { o.policy = &p }
+}
+
+func newOptions(duration time.Duration, opts []Option) options {
+	o := options{compressMinSize: defaultCompressMinSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.policy == nil {
+		o.policy = &TTLPolicy{Default: duration}
+	}
+	return o
+}
+
+func (o options) ttlFor(key string) time.Duration {
+	return o.policy.TTLFor(key)
+}
@@ -0,0 +1,25 @@
+package pokecache
+
+import (
+	"sync/atomic"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+)
+
+type counters struct {
+	hits            atomic.Uint64
+	misses          atomic.Uint64
+	revalidations   atomic.Uint64
+	evictedTTL      atomic.Uint64
+	evictedCapacity atomic.Uint64
+}
+
+func (c *counters) stats() domain.CacheStats {
+	return domain.CacheStats{
+		Hits:            c.hits.Load(),
+		Misses:          c.misses.Load(),
+		Revalidations:   c.revalidations.Load(),
+		EvictedTTL:      c.evictedTTL.Load(),
+		EvictedCapacity: c.evictedCapacity.Load(),
+	}
+}
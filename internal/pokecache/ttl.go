@@ -0,0 +1,64 @@
+package pokecache
+
+import (
+	"regexp"
+	"time"
+)
+
+// Forever marks entries that never expire.
+const Forever time.Duration = -1
+
+type TTLRule struct {
+	Pattern *regexp.Regexp
+	TTL     time.Duration
+}
+
+// TTLPolicy picks an entry's lifetime from the first rule whose pattern
+// matches its key, falling back to Default.
+type TTLPolicy struct {
+	Rules   []TTLRule
+	Default time.Duration
+}
+
+func (p TTLPolicy) TTLFor(key string) time.Duration {
+	for _, rule := range p.Rules {
+		if rule.Pattern.MatchString(key) {
+			return rule.TTL
+		}
+	}
+	return p.Default
+}
+
+// DefaultTTLPolicy keeps Pokemon forever and location-area list pages for a
+// day. Every other key lives for def.
+func DefaultTTLPolicy(def time.Duration) TTLPolicy {
+	return TTLPolicy{
+		Rules: []TTLRule{
+			{Pattern: regexp.MustCompile(`/api/v2/pokemon/[^/?]+/?$`), TTL: Forever},
+			{Pattern: regexp.MustCompile(`/api/v2/location-area/?(\?.*)?$`), TTL: 24 * time.Hour},
+		},
+		Default: def,
+	}
+}
+
+// expiresAt returns the zero time for entries that never expire.
+func expiresAt(createdAt time.Time, ttl time.Duration) time.Time {
+	if ttl < 0 {
+		return time.Time{}
+	}
+	return createdAt.Add(ttl)
+}
+
+func expiredAt(createdAt time.Time, ttl time.Duration, now time.Time) bool {
+	exp := expiresAt(createdAt, ttl)
+	return !exp.IsZero() && exp.Before(now)
+}
+
+// reapAt is when an entry can be dropped. Entries with validators are kept
+// for staleFactor lifetimes so they can still be revalidated.
+func reapAt(createdAt time.Time, ttl time.Duration, hasValidators bool) time.Time {
+	if hasValidators && ttl > 0 {
+		ttl *= staleFactor
+	}
+	return expiresAt(createdAt, ttl)
+}
@@ -0,0 +1,84 @@
+package pokecache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultTTLPolicy(t *testing.T) {
+	t.Parallel()
+	policy := DefaultTTLPolicy(time.Minute)
+
+	tests := []struct {
+		name string
+		key  string
+		want time.Duration
+	}{
+		{name: "pokemon by name", key: "https://pokeapi.co/api/v2/pokemon/pikachu", want: Forever},
+		{name: "pokemon by id with slash", key: "https://pokeapi.co/api/v2/pokemon/25/", want: Forever},
+		{name: "first location area page", key: "https://pokeapi.co/api/v2/location-area/", want: 24 * time.Hour},
+		{name: "later location area page", key: "https://pokeapi.co/api/v2/location-area/?offset=20&limit=20", want: 24 * time.Hour},
+		{name: "single location area", key: "https://pokeapi.co/api/v2/location-area/canalave-city-area", want: time.Minute},
+		{name: "pokemon species", key: "https://pokeapi.co/api/v2/pokemon-species/25/", want: time.Minute},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := policy.TTLFor(tc.key); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCacheHonorsPerEntryTTL(t *testing.T) {
+	t.Parallel()
+	cache := NewCache(50*time.Millisecond, WithTTLPolicy(DefaultTTLPolicy(50*time.Millisecond)))
+	defer cache.Done()
+
+	pokemon := "https://pokeapi.co/api/v2/pokemon/mew"
+	area := "https://pokeapi.co/api/v2/location-area/canalave-city-area"
+	for _, key := range []string{pokemon, area} {
+		if err := cache.Add(key, []byte(key)); err != nil {
+			t.Fatalf("error adding key %s: %v", key, err)
+		}
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := cache.Get(pokemon); err != nil {
+		t.Fatalf("expected pokemon to never expire, got %v", err)
+	}
+	if _, _, err := cache.GetStale(area); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected area to be reaped, got %v", err)
+	}
+	if got := cache.Stats().EvictedTTL; got != 1 {
+		t.Fatalf("expected 1 TTL eviction, got %d", got)
+	}
+}
+
+func TestDiskCacheHonorsPerEntryTTL(t *testing.T) {
+	t.Parallel()
+	cache, err := NewDiskCache(t.TempDir(), 50*time.Millisecond, WithTTLPolicy(DefaultTTLPolicy(50*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("expected disk cache, got %v", err)
+	}
+	defer cache.Done()
+
+	pokemon := "https://pokeapi.co/api/v2/pokemon/mew"
+	area := "https://pokeapi.co/api/v2/location-area/canalave-city-area"
+	for _, key := range []string{pokemon, area} {
+		if err = cache.Add(key, []byte(key)); err != nil {
+			t.Fatalf("error adding key %s: %v", key, err)
+		}
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err = cache.Get(pokemon); err != nil {
+		t.Fatalf("expected pokemon to never expire, got %v", err)
+	}
+	if _, _, err = cache.GetStale(area); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected area to be reaped, got %v", err)
+	}
+}
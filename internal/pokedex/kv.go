@@ -0,0 +1,129 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+)
+
+const (
+	storeInstancePrefix = "pokedex/instance/"
+	storeVersionKey     = "pokedex/version"
+
+	// storePokemonPrefix held one record per species before SaveVersion 2.
+	// Those records are read on Load and removed by the next Save.
+	storePokemonPrefix = "pokedex/pokemon/"
+)
+
+// StorePokedex is a Pokedex persisted in a kvstore.Store, one record per
+// caught instance. Save only writes the records that changed since the last
+// Load or Save, so it stays cheap as the collection grows. How soon a Save
+// reaches the disk depends on the store's sync policy.
+type StorePokedex struct {
+	*Pokedex
+	store   *kvstore.Store
+	saveMu  sync.Mutex
+	saved   map[string][]byte
+	version int
+}
+
+func NewStorePokedex(store *kvstore.Store) *StorePokedex {
+	return &StorePokedex{
+		Pokedex: NewPokedex(),
+		store:   store,
+		saved:   make(map[string][]byte),
+	}
+}
+
+// Load replaces the in-memory Pokedex with the Pokemon in the store. Records
+// that can't be decoded are skipped and reported with ErrCorruptSave.
+func (s *StorePokedex) Load() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	if raw, err := s.store.Get(storeVersionKey); err == nil {
+		version, err := strconv.Atoi(string(raw))
+		if err != nil {
+			return fmt.Errorf("%w: bad version: %v", ErrCorruptSave, err)
+		}
+		if version > SaveVersion || version < 1 {
+			return fmt.Errorf("%w: %d", ErrUnsupportedSaveVersion, version)
+		}
+		s.version = version
+	} else if !errors.Is(err, kvstore.ErrNotFound) {
+		return err
+	}
+
+	var pokemon []Pokemon
+	saved := make(map[string][]byte)
+	var errs []error
+	keys := append(s.store.Keys(storeInstancePrefix), s.store.Keys(storePokemonPrefix)...)
+	for _, key := range keys {
+		data, err := s.store.Get(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var p Pokemon
+		if err = json.Unmarshal(data, &p); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s: %v", ErrCorruptSave, key, err))
+			continue
+		}
+		pokemon = append(pokemon, p)
+		saved[key] = data
+	}
+
+	s.replace(pokemon)
+	s.saved = saved
+	return errors.Join(errs...)
+}
+
+func (s *StorePokedex) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	// The version only changes when the store is created or migrated.
+	if s.version != SaveVersion {
+		if err := s.store.Put(storeVersionKey, []byte(strconv.Itoa(SaveVersion))); err != nil {
+			return err
+		}
+		s.version = SaveVersion
+	}
+	current := make(map[string][]byte)
+	for _, p := range s.GetAllPokemon() {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		key := storeInstancePrefix + strconv.Itoa(p.InstanceID)
+		current[key] = data
+		if string(s.saved[key]) == string(data) {
+			continue
+		}
+		if err = s.store.Put(key, data); err != nil {
+			return err
+		}
+		s.saved[key] = data
+	}
+	for key := range s.saved {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		if err := s.store.Delete(key); err != nil {
+			return err
+		}
+		delete(s.saved, key)
+	}
+	return nil
+}
+
+// Empty reports whether the store holds no Pokedex at all, as opposed to an
+// empty one that was saved.
+func (s *StorePokedex) Empty() bool {
+	_, err := s.store.Get(storeVersionKey)
+	return errors.Is(err, kvstore.ErrNotFound)
+}
@@ -0,0 +1,163 @@
+package pokedex
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+)
+
+func openStore(t *testing.T, path string) *kvstore.Store {
+	t.Helper()
+	store, err := kvstore.Open(path)
+	if err != nil {
+		t.Fatalf("expected store, got %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestStorePokedexSaveAndLoad(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	store := openStore(t, path)
+
+	p := NewStorePokedex(store)
+	if !p.Empty() {
+		t.Fatal("expected a new store to be empty")
+	}
+	p.Add(Pokemon{Name: "mew", Weight: 40})
+	p.Add(Pokemon{Name: "pikachu", Weight: 60})
+	p.Add(Pokemon{Name: "psyduck", Weight: 196})
+	if err := p.Save(); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+	size := store.Stats().FileBytes
+	if err := p.Save(); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+	if grown := store.Stats().FileBytes - size; grown != 0 {
+		t.Fatalf("expected an unchanged save to write nothing, grew %d bytes", grown)
+	}
+
+	p.Remove(Pokemon{Name: "psyduck"})
+	pikachu, err := p.GetPokemonByName("pikachu")
+	if err != nil {
+		t.Fatalf("expected pikachu, got %v", err)
+	}
+	pikachu.Weight = 61
+	p.Add(pikachu)
+	if err = p.Save(); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	loaded := NewStorePokedex(openStore(t, path))
+	if err = loaded.Load(); err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	if loaded.Empty() {
+		t.Fatal("expected a saved store not to be empty")
+	}
+	if got := len(loaded.GetAllPokemon()); got != 2 {
+		t.Fatalf("expected 2 pokemon, got %d", got)
+	}
+	got, err := loaded.GetInstance(pikachu.InstanceID)
+	if err != nil || got.Weight != 61 {
+		t.Fatalf("expected updated pikachu, got %+v, %v", got, err)
+	}
+	if _, err = loaded.GetPokemonByName("psyduck"); !errors.Is(err, ErrPokemonNotFound) {
+		t.Fatalf("expected released pokemon to be gone, got %v", err)
+	}
+}
+
+func TestStorePokedexLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		records map[string]string
+		wantErr error
+		want    int
+	}{
+		{
+			name:    "newer version",
+			records: map[string]string{storeVersionKey: "99", storePokemonPrefix + "mew": `{"name":"mew"}`},
+			wantErr: ErrUnsupportedSaveVersion,
+		},
+		{
+			name:    "bad version",
+			records: map[string]string{storeVersionKey: "one"},
+			wantErr: ErrCorruptSave,
+		},
+		{
+			name: "corrupt record is skipped",
+			records: map[string]string{
+				storeVersionKey:           "2",
+				storeInstancePrefix + "1": `{"instance_id":1,"name":"mew"}`,
+				storeInstancePrefix + "2": `{"name":`,
+			},
+			wantErr: ErrCorruptSave,
+			want:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			store := openStore(t, filepath.Join(t.TempDir(), "pokedex.db"))
+			for key, val := range tc.records {
+				if err := store.Put(key, []byte(val)); err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+			}
+			p := NewStorePokedex(store)
+			if err := p.Load(); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if got := len(p.GetAllPokemon()); got != tc.want {
+				t.Fatalf("expected %d pokemon, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStorePokedexMigratesSpeciesRecords(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	store := openStore(t, path)
+	records := map[string]string{
+		storeVersionKey:                "1",
+		storePokemonPrefix + "pikachu": `{"name":"pikachu","weight":60}`,
+		storePokemonPrefix + "mew":     `{"name":"mew","weight":40}`,
+	}
+	for key, val := range records {
+		if err := store.Put(key, []byte(val)); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	p := NewStorePokedex(store)
+	if err := p.Load(); err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	all := p.GetAllPokemon()
+	if len(all) != 2 || all[0].Name != "mew" || all[0].InstanceID != 1 || all[1].InstanceID != 2 {
+		t.Fatalf("expected instance IDs assigned in name order, got %+v", all)
+	}
+	if err := p.Save(); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+	if keys := store.Keys(storePokemonPrefix); len(keys) != 0 {
+		t.Fatalf("expected species records to be removed, got %v", keys)
+	}
+	if keys := store.Keys(storeInstancePrefix); len(keys) != 2 {
+		t.Fatalf("expected 2 instance records, got %v", keys)
+	}
+	if version, err := store.Get(storeVersionKey); err != nil || string(version) != "2" {
+		t.Fatalf("expected version 2, got %q, %v", string(version), err)
+	}
+}
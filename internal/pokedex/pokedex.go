@@ -2,19 +2,30 @@ package pokedex
 
 import (
 	"errors"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 var ErrPokemonNotFound = errors.New("pokemon not found")
 
+// Pokemon is one caught instance. InstanceID is unique within a Pokedex and
+// is assigned by Add; Location is empty when the catch happened without a
+// preceding explore of an area where the species appears.
 type Pokemon struct {
-	Id             int    `json:"id"`
-	Name           string `json:"name"`
-	BaseExperience int    `json:"base_experience"`
-	Height         int    `json:"height"`
-	IsDefault      bool   `json:"is_default"`
-	Order          int    `json:"order"`
-	Weight         int    `json:"weight"`
+	InstanceID     int       `json:"instance_id"`
+	CaughtAt       time.Time `json:"caught_at"`
+	Location       string    `json:"location,omitempty"`
+	Level          int       `json:"level"`
+	Id             int       `json:"id"`
+	Name           string    `json:"name"`
+	BaseExperience int       `json:"base_experience"`
+	Height         int       `json:"height"`
+	IsDefault      bool      `json:"is_default"`
+	Order          int       `json:"order"`
+	Weight         int       `json:"weight"`
 	Abilities      []struct {
 		IsHidden bool `json:"is_hidden"`
 		Slot     int  `json:"slot"`
@@ -38,28 +49,91 @@ type Pokemon struct {
 			Url  string `json:"url"`
 		} `json:"type"`
 	} `json:"types"`
+	Moves []struct {
+		Move struct {
+			Name string `json:"name"`
+			Url  string `json:"url"`
+		} `json:"move"`
+		VersionGroupDetails []struct {
+			LevelLearnedAt int `json:"level_learned_at"`
+			VersionGroup   struct {
+				Name string `json:"name"`
+				Url  string `json:"url"`
+			} `json:"version_group"`
+			MoveLearnMethod struct {
+				Name string `json:"name"`
+				Url  string `json:"url"`
+			} `json:"move_learn_method"`
+		} `json:"version_group_details"`
+	} `json:"moves,omitempty"`
+	HeldItem string `json:"held_item,omitempty"`
+	Species  struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"species"`
+	Sprites Sprites     `json:"sprites"`
+	History []Evolution `json:"history,omitempty"`
+}
+
+// Evolution records one evolution of an instance, oldest first in History.
+type Evolution struct {
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	Level int       `json:"level"`
+	At    time.Time `json:"at"`
+}
+
+// Sprites are the image URLs kept for a caught pokemon. Any of them may be
+// empty when PokeAPI has no such image.
+type Sprites struct {
+	FrontDefault    string `json:"front_default,omitempty"`
+	FrontShiny      string `json:"front_shiny,omitempty"`
+	BackDefault     string `json:"back_default,omitempty"`
+	OfficialArtwork string `json:"official_artwork,omitempty"`
+}
+
+// Encounter is where a species can be found and at which levels.
+type Encounter struct {
+	Area     string
+	MinLevel int
+	MaxLevel int
 }
 
 type Pokedex struct {
-	Owned map[string]Pokemon
-	mu    sync.RWMutex
+	Owned  map[int]Pokemon
+	nextID int
+	mu     sync.RWMutex
 }
 
-func (p *Pokedex) Add(pokemon Pokemon) {
+// Add stores pokemon and returns it with its InstanceID filled in.
+func (p *Pokedex) Add(pokemon Pokemon) Pokemon {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.Owned[pokemon.Name] = pokemon
+	if pokemon.InstanceID == 0 {
+		p.nextID++
+		pokemon.InstanceID = p.nextID
+	} else if pokemon.InstanceID > p.nextID {
+		p.nextID = pokemon.InstanceID
+	}
+	p.Owned[pokemon.InstanceID] = pokemon
+	return pokemon
 }
 
+// Remove deletes the instance with pokemon's InstanceID, or the earliest
+// caught instance of its species when no InstanceID is set.
 func (p *Pokedex) Remove(pokemon Pokemon) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	_, ok := p.Owned[pokemon.Name]
-	if ok {
-		delete(p.Owned, pokemon.Name)
-
+	if pokemon.InstanceID != 0 {
+		delete(p.Owned, pokemon.InstanceID)
+		return
+	}
+	if instances := p.instances(pokemon.Name); len(instances) > 0 {
+		delete(p.Owned, instances[0].InstanceID)
 	}
 }
+
+// GetAllPokemon returns every instance ordered by InstanceID.
 func (p *Pokedex) GetAllPokemon() []Pokemon {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -67,23 +141,95 @@ func (p *Pokedex) GetAllPokemon() []Pokemon {
 	for _, pokemon := range p.Owned {
 		allPokemon = append(allPokemon, pokemon)
 	}
+	sortByInstance(allPokemon)
 	return allPokemon
 }
 
+// GetPokemonByName returns the earliest caught instance of a species.
 func (p *Pokedex) GetPokemonByName(name string) (Pokemon, error) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
+	if instances := p.instances(name); len(instances) > 0 {
+		return instances[0], nil
+	}
+	return Pokemon{}, ErrPokemonNotFound
+}
+
+// GetInstances returns every instance of a species ordered by InstanceID.
+func (p *Pokedex) GetInstances(name string) []Pokemon {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.instances(name)
+}
+
+func (p *Pokedex) GetInstance(id int) (Pokemon, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	pokemon, ok := p.Owned[id]
+	if !ok {
+		return Pokemon{}, ErrPokemonNotFound
+	}
+	return pokemon, nil
+}
+
+func (p *Pokedex) instances(name string) []Pokemon {
+	instances := make([]Pokemon, 0)
 	for _, pokemon := range p.Owned {
 		if pokemon.Name == name {
-			return pokemon, nil
+			instances = append(instances, pokemon)
 		}
 	}
-	return Pokemon{}, ErrPokemonNotFound
+	sortByInstance(instances)
+	return instances
+}
+
+// replace swaps in a loaded collection. Pokemon from saves that predate
+// instance tracking have no InstanceID (or share one); they get fresh IDs in
+// name order so the assignment is the same every time the save is loaded.
+func (p *Pokedex) replace(pokemon []Pokemon) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Owned = make(map[int]Pokemon, len(pokemon))
+	p.nextID = 0
+	var unassigned []Pokemon
+	for _, pm := range pokemon {
+		if _, taken := p.Owned[pm.InstanceID]; pm.InstanceID <= 0 || taken {
+			pm.InstanceID = 0
+			unassigned = append(unassigned, pm)
+			continue
+		}
+		p.Owned[pm.InstanceID] = pm
+		p.nextID = max(p.nextID, pm.InstanceID)
+	}
+	sort.SliceStable(unassigned, func(i, j int) bool { return unassigned[i].Name < unassigned[j].Name })
+	for _, pm := range unassigned {
+		p.nextID++
+		pm.InstanceID = p.nextID
+		p.Owned[pm.InstanceID] = pm
+	}
+}
+
+func sortByInstance(pokemon []Pokemon) {
+	sort.Slice(pokemon, func(i, j int) bool { return pokemon[i].InstanceID < pokemon[j].InstanceID })
+}
+
+// ParseInstanceID reads an instance reference typed as "#12". A bare number
+// is not one: commands read it as a Pokedex number, like lookup does.
+func ParseInstanceID(arg string) (int, bool) {
+	digits, ok := strings.CutPrefix(arg, "#")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(digits)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
 }
 
 func NewPokedex() *Pokedex {
 	return &Pokedex{
-		Owned: make(map[string]Pokemon),
+		Owned: make(map[int]Pokemon),
 		mu:    sync.RWMutex{},
 	}
 
@@ -50,3 +50,58 @@ func TestGetPokemonByNameNotFound(t *testing.T) {
 		t.Fatalf("expected ErrPokemonNotFound, got %v", err)
 	}
 }
+
+func TestPokedexInstances(t *testing.T) {
+	t.Parallel()
+
+	p := NewPokedex()
+	first := p.Add(Pokemon{Name: "pidgey", Level: 3})
+	mew := p.Add(Pokemon{Name: "mew"})
+	second := p.Add(Pokemon{Name: "pidgey", Level: 7})
+	if first.InstanceID != 1 || mew.InstanceID != 2 || second.InstanceID != 3 {
+		t.Fatalf("expected sequential instance IDs, got %d, %d, %d", first.InstanceID, mew.InstanceID, second.InstanceID)
+	}
+
+	instances := p.GetInstances("pidgey")
+	if len(instances) != 2 || instances[0].Level != 3 || instances[1].Level != 7 {
+		t.Fatalf("expected both pidgey in catch order, got %+v", instances)
+	}
+	got, err := p.GetInstance(second.InstanceID)
+	if err != nil || got.Level != 7 {
+		t.Fatalf("expected second pidgey, got %+v, %v", got, err)
+	}
+	if _, err = p.GetInstance(99); !errors.Is(err, ErrPokemonNotFound) {
+		t.Fatalf("expected ErrPokemonNotFound, got %v", err)
+	}
+
+	p.Remove(second)
+	if instances = p.GetInstances("pidgey"); len(instances) != 1 || instances[0].InstanceID != first.InstanceID {
+		t.Fatalf("expected only the first pidgey to remain, got %+v", instances)
+	}
+	if next := p.Add(Pokemon{Name: "pidgey"}); next.InstanceID != 4 {
+		t.Fatalf("expected IDs not to be reused, got %d", next.InstanceID)
+	}
+}
+
+func TestParseInstanceID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arg    string
+		want   int
+		wantOK bool
+	}{
+		{arg: "12"},
+		{arg: "#12", want: 12, wantOK: true},
+		{arg: "#3", want: 3, wantOK: true},
+		{arg: "pidgey"},
+		{arg: "#0"},
+		{arg: "#-1"},
+	}
+	for _, tc := range tests {
+		got, ok := ParseInstanceID(tc.arg)
+		if got != tc.want || ok != tc.wantOK {
+			t.Fatalf("ParseInstanceID(%q): expected %d, %v, got %d, %v", tc.arg, tc.want, tc.wantOK, got, ok)
+		}
+	}
+}
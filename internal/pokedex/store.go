@@ -0,0 +1,127 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/atomicfile"
+)
+
+// SaveVersion 2 added per-instance fields. Version 1 saves and the
+// unversioned legacy format are migrated on Load.
+const SaveVersion = 2
+
+var (
+	ErrCorruptSave            = errors.New("corrupt save file")
+	ErrUnsupportedSaveVersion = errors.New("unsupported save file version")
+)
+
+type saveFile struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"saved_at"`
+	Pokemon []Pokemon `json:"pokemon"`
+}
+
+// FilePokedex is a Pokedex that can be loaded from and saved to a JSON file.
+// Saves are atomic: the file is written to a temporary file in the same
+// directory and renamed over the previous save.
+type FilePokedex struct {
+	*Pokedex
+	path   string
+	saveMu sync.Mutex
+}
+
+func NewFilePokedex(path string) *FilePokedex {
+	return &FilePokedex{
+		Pokedex: NewPokedex(),
+		path:    path,
+	}
+}
+
+func (f *FilePokedex) Path() string {
+	return f.path
+}
+
+// Load replaces the in-memory Pokedex with the contents of the save file.
+// A missing file is not an error. A file that can't be decoded, or that was
+// written by a newer version, is moved aside to <path>.bak and the Pokedex
+// starts empty; the returned error wraps ErrCorruptSave or
+// ErrUnsupportedSaveVersion so the caller can report it.
+func (f *FilePokedex) Load() error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	pokemon, err := decodeSave(data)
+	if err != nil {
+		if backupErr := os.Rename(f.path, f.path+".bak"); backupErr != nil {
+			return errors.Join(err, backupErr)
+		}
+		return fmt.Errorf("%w (moved to %s.bak)", err, f.path)
+	}
+	f.replace(pokemon)
+	return nil
+}
+
+func (f *FilePokedex) Save() error {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+
+	all := f.GetAllPokemon()
+	data, err := json.MarshalIndent(saveFile{
+		Version: SaveVersion,
+		SavedAt: time.Now().UTC(),
+		Pokemon: all,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(f.path, data)
+}
+
+func decodeSave(data []byte) ([]Pokemon, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	rawVersion, ok := probe["version"]
+	if !ok {
+		return decodeLegacySave(data)
+	}
+	var version int
+	if err := json.Unmarshal(rawVersion, &version); err != nil {
+		return nil, fmt.Errorf("%w: bad version: %v", ErrCorruptSave, err)
+	}
+	if version > SaveVersion || version < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSaveVersion, version)
+	}
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	return save.Pokemon, nil
+}
+
+// decodeLegacySave reads the unversioned format, which is the Owned map
+// serialized as-is.
+func decodeLegacySave(data []byte) ([]Pokemon, error) {
+	var owned map[string]Pokemon
+	if err := json.Unmarshal(data, &owned); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	pokemon := make([]Pokemon, 0, len(owned))
+	for name, p := range owned {
+		if p.Name == "" {
+			p.Name = name
+		}
+		pokemon = append(pokemon, p)
+	}
+	return pokemon, nil
+}
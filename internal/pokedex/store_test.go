@@ -0,0 +1,163 @@
+package pokedex
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilePokedexSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "pokedex.json")
+	p := NewFilePokedex(path)
+	p.Add(Pokemon{Name: "mew", Height: 4, Weight: 40})
+	p.Add(Pokemon{Name: "pikachu", Height: 4, Weight: 60})
+	if err := p.Save(); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+
+	loaded := NewFilePokedex(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	if len(loaded.GetAllPokemon()) != 2 {
+		t.Fatalf("expected 2 pokemon, got %d", len(loaded.GetAllPokemon()))
+	}
+	got, err := loaded.GetPokemonByName("pikachu")
+	if err != nil {
+		t.Fatalf("expected pikachu, got error: %v", err)
+	}
+	if got.Weight != 60 {
+		t.Fatalf("expected weight 60, got %d", got.Weight)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("read dir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the save file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestFilePokedexLoadMissingFile(t *testing.T) {
+	t.Parallel()
+
+	p := NewFilePokedex(filepath.Join(t.TempDir(), "pokedex.json"))
+	if err := p.Load(); err != nil {
+		t.Fatalf("expected nil for missing file, got %v", err)
+	}
+	if len(p.GetAllPokemon()) != 0 {
+		t.Fatal("expected empty pokedex")
+	}
+}
+
+func TestFilePokedexLoadRecovers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{name: "corrupt json", content: "{not json", wantErr: ErrCorruptSave},
+		{name: "bad version", content: `{"version":"two"}`, wantErr: ErrCorruptSave},
+		{name: "newer version", content: `{"version":99,"pokemon":[]}`, wantErr: ErrUnsupportedSaveVersion},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pokedex.json")
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			p := NewFilePokedex(path)
+			err := p.Load()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if len(p.GetAllPokemon()) != 0 {
+				t.Fatal("expected empty pokedex after failed load")
+			}
+			backup, err := os.ReadFile(path + ".bak")
+			if err != nil {
+				t.Fatalf("expected backup file, got %v", err)
+			}
+			if string(backup) != tc.content {
+				t.Fatalf("expected backup to keep original content, got %q", string(backup))
+			}
+			if err = p.Save(); err != nil {
+				t.Fatalf("expected save after recovery to succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFilePokedexLoadLegacyFormat(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	legacy := `{"mew":{"id":151,"name":"mew","height":4,"weight":40},"pidgey":{"id":16,"height":3}}`
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	p := NewFilePokedex(path)
+	if err := p.Load(); err != nil {
+		t.Fatalf("expected legacy load to succeed, got %v", err)
+	}
+	if _, err := p.GetPokemonByName("mew"); err != nil {
+		t.Fatalf("expected mew, got %v", err)
+	}
+	got, err := p.GetPokemonByName("pidgey")
+	if err != nil {
+		t.Fatalf("expected pidgey named from map key, got %v", err)
+	}
+	if got.Id != 16 {
+		t.Fatalf("expected id 16, got %d", got.Id)
+	}
+}
+
+func TestFilePokedexMigratesVersion1(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	v1 := `{"version":1,"saved_at":"2024-01-01T00:00:00Z","pokemon":[{"name":"pidgey","id":16},{"name":"mew","id":151}]}`
+	if err := os.WriteFile(path, []byte(v1), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	p := NewFilePokedex(path)
+	if err := p.Load(); err != nil {
+		t.Fatalf("expected v1 load to succeed, got %v", err)
+	}
+	all := p.GetAllPokemon()
+	if len(all) != 2 || all[0].Name != "mew" || all[0].InstanceID != 1 || all[1].Name != "pidgey" || all[1].InstanceID != 2 {
+		t.Fatalf("expected instance IDs assigned in name order, got %+v", all)
+	}
+	caught := p.Add(Pokemon{Name: "pidgey", Level: 4})
+	if caught.InstanceID != 3 {
+		t.Fatalf("expected next instance ID 3, got %d", caught.InstanceID)
+	}
+	if err := p.Save(); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+
+	reloaded := NewFilePokedex(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	if got := reloaded.GetInstances("pidgey"); len(got) != 2 || got[1].Level != 4 {
+		t.Fatalf("expected both pidgey instances after reload, got %+v", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"version": 2`) {
+		t.Fatalf("expected save to be rewritten as version 2, got %s", string(data))
+	}
+}
@@ -1,11 +1,11 @@
 package run
 
-import "github.com/chzyer/readline"
+import (
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/chzyer/readline"
+)
 
-type LineReader interface {
-	Readline() (string, error)
-	Close() error
-}
+type LineReader = domain.LineReader
 
 type ReadlineInput struct {
 	rl *readline.Instance
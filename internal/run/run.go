@@ -1,23 +1,27 @@
 package run
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 
 	"github.com/Flarenzy/Pokedex/cmd"
 	"github.com/Flarenzy/Pokedex/internal/config"
 	"github.com/chzyer/readline"
 )
 
-func Run(c *config.Config, in LineReader, commands map[string]*cmd.CliCommand) error {
+func Run(ctx context.Context, c *config.Config, in LineReader, commands map[string]*cmd.CliCommand) error {
+	c.Input = in
 	for {
 		line, err := in.Readline()
 		if err != nil {
-			if errors.Is(err, readline.ErrInterrupt) {
+			if errors.Is(err, readline.ErrInterrupt) || errors.Is(err, io.EOF) {
 				c.Logger.Info("Interrupt received")
 				c.Cache.Done()
-				os.Exit(0)
+				return nil
 			}
 			c.Logger.Error(fmt.Sprintf("Error reading line: %s", err))
 		}
@@ -27,26 +31,52 @@ func Run(c *config.Config, in LineReader, commands map[string]*cmd.CliCommand) e
 		}
 
 		command, ok := commands[clearedInput[0]]
-		if len(clearedInput) > 1 {
-			var args []string
-			for _, arg := range clearedInput[1:] {
-				args = append(args, arg)
-			}
-			c.Args = args
-		}
+		c.Args = clearedInput[1:]
 		if !ok {
 			continue
 		}
-		err = command.Callback(c)
+		err = runCommand(ctx, c, command)
 		if err != nil {
 			if errors.Is(err, cmd.ErrStop) {
 				c.Cache.Done()
 				c.Logger.Info("Exit received")
 				return nil
 			}
+			if ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+				c.Logger.Info("Command stopped", "command", clearedInput[0], "error", err)
+				if err = reportStopped(c, err); err != nil {
+					return err
+				}
+				continue
+			}
 			c.Logger.Error(err.Error())
 			return err
 		}
 	}
+}
+
+// runCommand gives every command its own context so Ctrl-C, or the
+// configured timeout, only cancels the command that is running.
+func runCommand(ctx context.Context, c *config.Config, command *cmd.CliCommand) error {
+	cmdCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	if c.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		cmdCtx, cancel = context.WithTimeout(cmdCtx, c.CommandTimeout)
+		defer cancel()
+	}
+	return command.Callback(cmdCtx, c)
+}
+
+func reportStopped(c *config.Config, err error) error {
+	msg := "command cancelled"
+	if errors.Is(err, context.DeadlineExceeded) {
+		msg = fmt.Sprintf("command timed out after %v", c.CommandTimeout)
+	}
+	_, err = fmt.Fprintln(c.Out, msg)
+	if err != nil {
+		c.Logger.Error("Error writing response: ", "error", err)
+		return err
+	}
 	return nil
 }
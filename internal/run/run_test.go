@@ -1,14 +1,19 @@
 package run
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Flarenzy/Pokedex/cmd"
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
 	"github.com/Flarenzy/Pokedex/internal/pokedex"
 	"github.com/chzyer/readline"
 )
@@ -38,6 +43,11 @@ type runCache struct{ done bool }
 
 func (r *runCache) Get(key string) ([]byte, error)   { return nil, nil }
 func (r *runCache) Add(key string, val []byte) error { return nil }
+func (r *runCache) List(prefix string) ([]domain.CacheEntry, error) {
+	return nil, nil
+}
+func (r *runCache) Purge(prefix string) (int, error) { return 0, nil }
+func (r *runCache) Clear() error                     { return nil }
 func (r *runCache) Done()                            { r.done = true }
 
 func testConfig(cache *runCache) *config.Config {
@@ -73,7 +83,7 @@ func TestRunExecutesCommandAndStops(t *testing.T) {
 
 	called := false
 	commands := map[string]*cmd.CliCommand{
-		"catch": {Callback: func(cfg *config.Config) error {
+		"catch": {Callback: func(ctx context.Context, cfg *config.Config) error {
 			called = true
 			if len(cfg.Args) != 2 || cfg.Args[0] != "mew" || cfg.Args[1] != "mewtwo" {
 				t.Fatalf("args not parsed as expected: %v", cfg.Args)
@@ -83,7 +93,7 @@ func TestRunExecutesCommandAndStops(t *testing.T) {
 	}
 
 	in := &scriptReader{items: []scriptLine{{line: "CATCH   mew   MEWTWO", err: nil}}}
-	err := Run(c, in, commands)
+	err := Run(context.Background(), c, in, commands)
 	if err != nil {
 		t.Fatalf("expected nil, got %v", err)
 	}
@@ -95,6 +105,27 @@ func TestRunExecutesCommandAndStops(t *testing.T) {
 	}
 }
 
+func TestRunResetsArgsBetweenCommands(t *testing.T) {
+	t.Parallel()
+
+	c := testConfig(&runCache{})
+	var got [][]string
+	commands := map[string]*cmd.CliCommand{
+		"cache": {Callback: func(ctx context.Context, cfg *config.Config) error {
+			got = append(got, cfg.Args)
+			return nil
+		}},
+	}
+
+	in := &scriptReader{items: []scriptLine{{line: "cache list pokemon"}, {line: "cache"}}}
+	if err := Run(context.Background(), c, in, commands); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(got) != 2 || len(got[0]) != 2 || len(got[1]) != 0 {
+		t.Fatalf("expected args to be reset for the second command, got %v", got)
+	}
+}
+
 func TestRunReturnsCommandError(t *testing.T) {
 	t.Parallel()
 
@@ -102,11 +133,11 @@ func TestRunReturnsCommandError(t *testing.T) {
 	c := testConfig(cache)
 	expected := errors.New("boom")
 	commands := map[string]*cmd.CliCommand{
-		"x": {Callback: func(cfg *config.Config) error { return expected }},
+		"x": {Callback: func(ctx context.Context, cfg *config.Config) error { return expected }},
 	}
 
 	in := &scriptReader{items: []scriptLine{{line: "x", err: nil}}}
-	err := Run(c, in, commands)
+	err := Run(context.Background(), c, in, commands)
 	if !errors.Is(err, expected) {
 		t.Fatalf("expected %v, got %v", expected, err)
 	}
@@ -122,11 +153,11 @@ func TestRunContinuesOnUnknownCommand(t *testing.T) {
 	c := testConfig(cache)
 	called := false
 	commands := map[string]*cmd.CliCommand{
-		"exit": {Callback: func(cfg *config.Config) error { called = true; return cmd.ErrStop }},
+		"exit": {Callback: func(ctx context.Context, cfg *config.Config) error { called = true; return cmd.ErrStop }},
 	}
 	in := &scriptReader{items: []scriptLine{{line: "unknown", err: nil}, {line: "exit", err: nil}}}
 
-	err := Run(c, in, commands)
+	err := Run(context.Background(), c, in, commands)
 	if err != nil {
 		t.Fatalf("expected nil, got %v", err)
 	}
@@ -142,11 +173,11 @@ func TestRunHandlesReadlineErrorAndProcessesLine(t *testing.T) {
 	c := testConfig(cache)
 	called := false
 	commands := map[string]*cmd.CliCommand{
-		"stop": {Callback: func(cfg *config.Config) error { called = true; return cmd.ErrStop }},
+		"stop": {Callback: func(ctx context.Context, cfg *config.Config) error { called = true; return cmd.ErrStop }},
 	}
 	in := &scriptReader{items: []scriptLine{{line: "stop", err: errors.New("transient readline error")}}}
 
-	err := Run(c, in, commands)
+	err := Run(context.Background(), c, in, commands)
 	if err != nil {
 		t.Fatalf("expected nil, got %v", err)
 	}
@@ -177,3 +208,117 @@ func TestNewReadlineInputAndMethods(t *testing.T) {
 		t.Fatalf("expected close nil, got %v", err)
 	}
 }
+
+func TestRunContinuesAfterCancelledCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		callback func(ctx context.Context, cfg *config.Config) error
+		wantOut  string
+	}{
+		{
+			name: "cancelled",
+			callback: func(ctx context.Context, cfg *config.Config) error {
+				return fmt.Errorf("fetch failed: %w", context.Canceled)
+			},
+			wantOut: "command cancelled",
+		},
+		{
+			name:    "timed out",
+			timeout: 10 * time.Millisecond,
+			callback: func(ctx context.Context, cfg *config.Config) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantOut: "command timed out after 10ms",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := &runCache{}
+			c := testConfig(cache)
+			out := &bytes.Buffer{}
+			c.Out = out
+			c.CommandTimeout = tc.timeout
+			stopped := false
+			commands := map[string]*cmd.CliCommand{
+				"slow": {Callback: tc.callback},
+				"exit": {Callback: func(ctx context.Context, cfg *config.Config) error { stopped = true; return cmd.ErrStop }},
+			}
+			in := &scriptReader{items: []scriptLine{{line: "slow"}, {line: "exit"}}}
+
+			err := Run(context.Background(), c, in, commands)
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if !stopped {
+				t.Fatal("expected the REPL to keep running after the command stopped")
+			}
+			if !strings.Contains(out.String(), tc.wantOut) {
+				t.Fatalf("expected output to contain %q, got %q", tc.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestRunPassesContextToCommands(t *testing.T) {
+	t.Parallel()
+
+	type key struct{}
+	c := testConfig(&runCache{})
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	commands := map[string]*cmd.CliCommand{
+		"x": {Callback: func(ctx context.Context, cfg *config.Config) error {
+			if ctx.Value(key{}) != "value" {
+				t.Fatal("expected command context to derive from Run context")
+			}
+			return cmd.ErrStop
+		}},
+	}
+	in := &scriptReader{items: []scriptLine{{line: "x"}}}
+
+	if err := Run(ctx, c, in, commands); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestRunStopsOnInterruptAndEOF(t *testing.T) {
+	t.Parallel()
+
+	for _, readErr := range []error{readline.ErrInterrupt, io.EOF} {
+		cache := &runCache{}
+		c := testConfig(cache)
+		in := &scriptReader{items: []scriptLine{{line: "", err: readErr}}}
+		if err := Run(context.Background(), c, in, map[string]*cmd.CliCommand{}); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if !cache.done {
+			t.Fatalf("expected cache.Done on %v", readErr)
+		}
+	}
+}
+
+func TestRunSharesInputWithCommands(t *testing.T) {
+	t.Parallel()
+
+	c := testConfig(&runCache{})
+	var answer string
+	commands := map[string]*cmd.CliCommand{
+		"release": {Callback: func(ctx context.Context, cfg *config.Config) error {
+			var err error
+			answer, err = cfg.Input.Readline()
+			return err
+		}},
+	}
+
+	in := &scriptReader{items: []scriptLine{{line: "release mew"}, {line: "y"}}}
+	if err := Run(context.Background(), c, in, commands); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if answer != "y" {
+		t.Fatalf("expected the command to read the confirmation line, got %q", answer)
+	}
+}
@@ -0,0 +1,59 @@
+package singleflight
+
+import "sync"
+
+type call[T any] struct {
+	wg      sync.WaitGroup
+	val     T
+	err     error
+	waiting int
+}
+
+// Group deduplicates concurrent calls that share a key: while a call for a
+// key is running, later callers wait for it and receive its result instead
+// of starting their own. The zero value is ready to use.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// Do runs fn for key unless a call for key is already in flight, in which
+// case it waits for that call. shared reports whether the result was handed
+// to more than one caller.
+func (g *Group[T]) Do(key string, fn func() (T, error)) (val T, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.waiting++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+	c := &call[T]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err, false
+}
+
+// waiting returns how many callers are waiting for the in-flight call for
+// key, not counting the one running it. Tests use it to know when every
+// caller has joined.
+func (g *Group[T]) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.calls[key]; ok {
+		return c.waiting
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupDo(t *testing.T) {
+	t.Parallel()
+
+	var g Group[string]
+	val, err, shared := g.Do("key", func() (string, error) { return "value", nil })
+	if val != "value" || err != nil || shared {
+		t.Fatalf("expected value, nil, false; got %q, %v, %v", val, err, shared)
+	}
+}
+
+func TestGroupDoDeduplicates(t *testing.T) {
+	t.Parallel()
+
+	var g Group[[]byte]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	boom := errors.New("boom")
+
+	const waiters = 10
+	var wg sync.WaitGroup
+	results := make([]error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err, _ := g.Do("key", func() ([]byte, error) {
+				calls.Add(1)
+				<-release
+				return nil, boom
+			})
+			results[i] = err
+		}()
+	}
+	for g.waiting("key") < waiters-1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+	for i, err := range results {
+		if !errors.Is(err, boom) {
+			t.Fatalf("waiter %d: expected shared error, got %v", i, err)
+		}
+	}
+
+	if got := g.waiting("key"); got != 0 {
+		t.Fatalf("expected no waiters once the call finished, got %d", got)
+	}
+	_, _, _ = g.Do("key", func() ([]byte, error) { calls.Add(1); return nil, nil })
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected a new call once the first finished, got %d calls", got)
+	}
+}
@@ -0,0 +1,110 @@
+package typechart
+
+import "sync"
+
+// DamageRelations are the type's entries from PokeAPI's damage_relations,
+// reduced to type names.
+type DamageRelations struct {
+	DoubleDamageTo   []string
+	HalfDamageTo     []string
+	NoDamageTo       []string
+	DoubleDamageFrom []string
+	HalfDamageFrom   []string
+	NoDamageFrom     []string
+}
+
+// Chart is a damage multiplier matrix indexed by attacking and defending
+// type. It fills in as types are added; pairs it knows nothing about deal
+// normal damage.
+type Chart struct {
+	multipliers map[string]map[string]float64
+	loaded      map[string]bool
+	mu          sync.RWMutex
+}
+
+func New() *Chart {
+	return &Chart{
+		multipliers: make(map[string]map[string]float64),
+		loaded:      make(map[string]bool),
+	}
+}
+
+// Add records the relations of typeName in both directions, so adding the
+// defending types is enough to know every multiplier against them.
+func (c *Chart) Add(typeName string, rel DamageRelations) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range []struct {
+		types    []string
+		factor   float64
+		attacker bool
+	}{
+		{rel.DoubleDamageTo, 2, true},
+		{rel.HalfDamageTo, 0.5, true},
+		{rel.NoDamageTo, 0, true},
+		{rel.DoubleDamageFrom, 2, false},
+		{rel.HalfDamageFrom, 0.5, false},
+		{rel.NoDamageFrom, 0, false},
+	} {
+		for _, other := range r.types {
+			if r.attacker {
+				c.set(typeName, other, r.factor)
+			} else {
+				c.set(other, typeName, r.factor)
+			}
+		}
+	}
+	c.loaded[typeName] = true
+}
+
+func (c *Chart) set(attacker, defender string, factor float64) {
+	if c.multipliers[attacker] == nil {
+		c.multipliers[attacker] = make(map[string]float64)
+	}
+	c.multipliers[attacker][defender] = factor
+}
+
+// Has reports whether typeName has been added.
+func (c *Chart) Has(typeName string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loaded[typeName]
+}
+
+// Multiplier is the damage factor of an attacker type against a pokemon
+// with the given types; dual types multiply.
+func (c *Chart) Multiplier(attacker string, defenders ...string) float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.multiplier(attacker, defenders)
+}
+
+func (c *Chart) multiplier(attacker string, defenders []string) float64 {
+	m := 1.0
+	for _, defender := range defenders {
+		if factor, ok := c.multipliers[attacker][defender]; ok {
+			m *= factor
+		}
+	}
+	return m
+}
+
+// Defending returns every attacking type that doesn't deal normal damage to
+// a pokemon with the given types, with its multiplier.
+func (c *Chart) Defending(defenders ...string) map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]float64)
+	for attacker, against := range c.multipliers {
+		for _, defender := range defenders {
+			if _, ok := against[defender]; !ok {
+				continue
+			}
+			if m := c.multiplier(attacker, defenders); m != 1 {
+				result[attacker] = m
+			}
+			break
+		}
+	}
+	return result
+}
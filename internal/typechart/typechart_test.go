@@ -0,0 +1,78 @@
+package typechart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testChart() *Chart {
+	c := New()
+	c.Add("grass", DamageRelations{
+		DoubleDamageTo:   []string{"ground", "rock", "water"},
+		HalfDamageTo:     []string{"flying", "poison", "bug", "steel", "fire", "grass", "dragon"},
+		DoubleDamageFrom: []string{"flying", "poison", "bug", "fire", "ice"},
+		HalfDamageFrom:   []string{"ground", "water", "grass", "electric"},
+	})
+	c.Add("poison", DamageRelations{
+		DoubleDamageTo:   []string{"grass", "fairy"},
+		HalfDamageTo:     []string{"poison", "ground", "rock", "ghost"},
+		NoDamageTo:       []string{"steel"},
+		DoubleDamageFrom: []string{"ground", "psychic"},
+		HalfDamageFrom:   []string{"fighting", "poison", "bug", "grass", "fairy"},
+	})
+	c.Add("ghost", DamageRelations{
+		NoDamageTo:       []string{"normal"},
+		DoubleDamageFrom: []string{"ghost", "dark"},
+		HalfDamageFrom:   []string{"poison", "bug"},
+		NoDamageFrom:     []string{"normal", "fighting"},
+	})
+	return c
+}
+
+func TestMultiplier(t *testing.T) {
+	t.Parallel()
+
+	c := testChart()
+	tests := []struct {
+		name      string
+		attacker  string
+		defenders []string
+		want      float64
+	}{
+		{name: "super effective", attacker: "fire", defenders: []string{"grass"}, want: 2},
+		{name: "dual types cancel", attacker: "ground", defenders: []string{"grass", "poison"}, want: 1},
+		{name: "dual resistance", attacker: "grass", defenders: []string{"grass", "poison"}, want: 0.25},
+		{name: "dual weakness", attacker: "bug", defenders: []string{"grass"}, want: 2},
+		{name: "immune", attacker: "normal", defenders: []string{"ghost", "poison"}, want: 0},
+		{name: "learned from attacker side", attacker: "poison", defenders: []string{"steel"}, want: 0},
+		{name: "unknown pair", attacker: "dragon", defenders: []string{"water"}, want: 1},
+	}
+	for _, tc := range tests {
+		if got := c.Multiplier(tc.attacker, tc.defenders...); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestDefending(t *testing.T) {
+	t.Parallel()
+
+	c := testChart()
+	want := map[string]float64{
+		"flying":   2,
+		"fire":     2,
+		"ice":      2,
+		"psychic":  2,
+		"fighting": 0.5,
+		"water":    0.5,
+		"electric": 0.5,
+		"fairy":    0.5,
+		"grass":    0.25,
+	}
+	if got := c.Defending("grass", "poison"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if !c.Has("ghost") || c.Has("fire") {
+		t.Fatal("expected only added types to be loaded")
+	}
+}
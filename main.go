@@ -1,18 +1,21 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/Flarenzy/Pokedex/cmd"
 	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
 	internalHTTP "github.com/Flarenzy/Pokedex/internal/http"
 	"github.com/Flarenzy/Pokedex/internal/logging"
 	"github.com/Flarenzy/Pokedex/internal/pokecache"
-	"github.com/Flarenzy/Pokedex/internal/pokedex"
 	"github.com/Flarenzy/Pokedex/internal/run"
 	"github.com/chzyer/readline"
 )
@@ -26,10 +29,19 @@ func cleanInput(text string) []string {
 	return res
 }
 
+func dataDir() string {
+	if dir := os.Getenv("POKEDEX_DATA_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".pokedex"
+	}
+	return filepath.Join(home, ".pokedex")
+}
+
 func main() {
 	commands := cmd.NewCommands()
-	cache := pokecache.NewCache(50 * time.Second)
-	myPokedex := pokedex.NewPokedex()
 	l, err := readline.NewEx(&readline.Config{
 		Prompt:          "Pokedex>",
 		InterruptPrompt: "^C",
@@ -40,7 +52,6 @@ func main() {
 
 		panic(err)
 	}
-	l.CaptureExitSignal()
 	rl := run.NewReadlineInput(l)
 	defer func() {
 		err = l.Close()
@@ -56,9 +67,34 @@ func main() {
 		Level: slog.LevelDebug,
 	}
 	logger := logging.NewLogger(logLevel)
-	httpClient := internalHTTP.NewDefaultHTTPClient()
+	store, err := openStorage(logger, dataDir(), os.Getenv("POKEDEX_STORAGE"))
+	if err != nil {
+		logger.Error("Error opening store", "error", err)
+		fmt.Fprintf(os.Stderr, "could not open the Pokedex store: %v\n", err)
+		os.Exit(1)
+	}
+	var cache domain.Cacher = pokecache.NewCache(50*time.Second,
+		pokecache.WithTTLPolicy(pokecache.DefaultTTLPolicy(50*time.Second)),
+		pokecache.WithMaxBytes(int64(config.IntFromEnv("POKEDEX_CACHE_MAX_BYTES", 64<<20))),
+		pokecache.WithMaxEntries(config.IntFromEnv("POKEDEX_CACHE_MAX_ENTRIES", 0)),
+	)
+	if store.cache != nil {
+		cache = pokecache.NewLayered(cache, store.cache)
+	}
+	bucket := internalHTTP.NewTokenBucket(
+		config.FloatFromEnv("POKEDEX_RATE_LIMIT", 5),
+		config.IntFromEnv("POKEDEX_RATE_BURST", 10),
+	)
+	retryPolicy := internalHTTP.DefaultRetryPolicy()
+	retryPolicy.MaxAttempts = config.IntFromEnv("POKEDEX_HTTP_MAX_ATTEMPTS", retryPolicy.MaxAttempts)
+	retryPolicy.MaxElapsed = config.DurationFromEnv("POKEDEX_HTTP_MAX_ELAPSED", retryPolicy.MaxElapsed)
+	httpClient := internalHTTP.NewRetryClient(
+		internalHTTP.NewRateLimitedClient(internalHTTP.NewDefaultHTTPClient(), bucket, logger),
+		retryPolicy,
+		logger,
+	)
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	c := config.NewConfig(cache, logger, myPokedex, httpClient, r.Float64)
+	c := config.NewConfig(cache, logger, store.pokedex, httpClient, r.Float64)
 	if areaURL := os.Getenv("POKEDEX_AREA_URL"); areaURL != "" {
 		c.AreaURL = areaURL
 		c.Next = areaURL
@@ -66,7 +102,36 @@ func main() {
 	if pokemonURL := os.Getenv("POKEDEX_POKEMON_URL"); pokemonURL != "" {
 		c.PokemonURL = pokemonURL
 	}
-	if err = run.Run(c, rl, commands); err != nil {
+	if speciesURL := os.Getenv("POKEDEX_SPECIES_URL"); speciesURL != "" {
+		c.SpeciesURL = speciesURL
+	}
+	if typeURL := os.Getenv("POKEDEX_TYPE_URL"); typeURL != "" {
+		c.TypeURL = typeURL
+	}
+	if language := os.Getenv("POKEDEX_LANGUAGE"); language != "" {
+		c.Language = language
+	}
+	c.DataDir = dataDir()
+	if store.store != nil {
+		c.Compactor = store.store
+	}
+	c.Offline = config.BoolFromEnv("POKEDEX_OFFLINE", false)
+	if fixturesDir := os.Getenv("POKEDEX_FIXTURES_DIR"); fixturesDir != "" {
+		c.FixturesDir = fixturesDir
+	}
+	c.CommandTimeout = config.DurationFromEnv("POKEDEX_COMMAND_TIMEOUT", 0)
+	c.MaxWorkers = config.IntFromEnv("POKEDEX_WORKERS", c.MaxWorkers)
+	c.PageSize = config.IntFromEnv("POKEDEX_PAGE_SIZE", c.PageSize)
+	err = run.Run(context.Background(), c, rl, commands)
+	if reporter, ok := cache.(domain.StatsReporter); ok {
+		stats := reporter.Stats()
+		logger.Info("Cache stats", "hits", stats.Hits, "misses", stats.Misses, "revalidations", stats.Revalidations,
+			"evicted_ttl", stats.EvictedTTL, "evicted_capacity", stats.EvictedCapacity)
+	}
+	if closeErr := store.Close(); closeErr != nil {
+		logger.Error("Error closing store", "error", closeErr)
+	}
+	if err != nil {
 		c.Logger.Error(err.Error())
 		os.Exit(1)
 	}
@@ -59,6 +59,7 @@ func TestMainE2EWithFixtures(t *testing.T) {
 	cmd.Env = append(os.Environ(),
 		"POKEDEX_AREA_URL="+ts.URL+"/api/v2/location-area/",
 		"POKEDEX_POKEMON_URL="+ts.URL+"/api/v2/pokemon/",
+		"POKEDEX_DATA_DIR="+t.TempDir(),
 	)
 	cmd.Stdin = strings.NewReader(script)
 
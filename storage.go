@@ -0,0 +1,120 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Flarenzy/Pokedex/internal/config"
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+	"github.com/Flarenzy/Pokedex/internal/pokecache"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+const diskCacheTTL = 24 * time.Hour
+
+// storage is the persistent half of the app: the Pokedex and the cache layer
+// that sits behind the in-memory one. store is nil unless the embedded
+// key-value store backs them.
+type storage struct {
+	pokedex domain.Pokedexer
+	cache   domain.Cacher
+	store   *kvstore.Store
+}
+
+func (s storage) Close() error {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Close()
+}
+
+// openStorage opens the named backend: "files" (the default) keeps a JSON
+// save file and a directory of cache files, "kv" keeps both in a single
+// kvstore log. A kv store that can't be opened is an error rather than a
+// reason to fall back to files: catches saved there would be missing from
+// the store once it opens again.
+func openStorage(logger *slog.Logger, dir, backend string) (storage, error) {
+	if backend == "kv" {
+		return openStoreStorage(logger, dir)
+	}
+	return openFileStorage(logger, dir), nil
+}
+
+func openFileStorage(logger *slog.Logger, dir string) storage {
+	p := pokedex.NewFilePokedex(filepath.Join(dir, "pokedex.json"))
+	if err := p.Load(); err != nil {
+		logger.Error("Error loading pokedex, starting with an empty one", "path", p.Path(), "error", err)
+	}
+	s := storage{pokedex: p}
+	diskCache, err := pokecache.NewDiskCache(filepath.Join(dir, "cache"), diskCacheTTL,
+		pokecache.WithTTLPolicy(pokecache.DefaultTTLPolicy(diskCacheTTL)))
+	if err != nil {
+		logger.Error("Error opening disk cache, using memory only", "error", err)
+		return s
+	}
+	s.cache = diskCache
+	return s
+}
+
+func openStoreStorage(logger *slog.Logger, dir string) (storage, error) {
+	store, err := kvstore.Open(filepath.Join(dir, "pokedex.db"),
+		kvstore.WithSyncPolicy(syncPolicy(os.Getenv("POKEDEX_KV_SYNC"))),
+		kvstore.WithSyncInterval(config.DurationFromEnv("POKEDEX_KV_SYNC_INTERVAL", time.Second)),
+	)
+	if err != nil {
+		return storage{}, err
+	}
+	if stats := store.Stats(); stats.Recovered > 0 {
+		logger.Warn("Dropped a torn write at the end of the store", "bytes", stats.Recovered)
+	}
+
+	p := pokedex.NewStorePokedex(store)
+	if err = p.Load(); err != nil {
+		logger.Error("Error loading pokedex from store", "error", err)
+	}
+	if p.Empty() {
+		importFileSave(logger, p, filepath.Join(dir, "pokedex.json"))
+	}
+	return storage{
+		pokedex: p,
+		cache: pokecache.NewStoreCache(store, diskCacheTTL,
+			pokecache.WithTTLPolicy(pokecache.DefaultTTLPolicy(diskCacheTTL))),
+		store: store,
+	}, nil
+}
+
+// importFileSave copies an existing JSON save into a fresh store so switching
+// backends keeps the collection.
+func importFileSave(logger *slog.Logger, p *pokedex.StorePokedex, path string) {
+	legacy := pokedex.NewFilePokedex(path)
+	if err := legacy.Load(); err != nil {
+		logger.Error("Error loading pokedex to import", "path", path, "error", err)
+		return
+	}
+	all := legacy.GetAllPokemon()
+	if len(all) == 0 {
+		return
+	}
+	for _, pokemon := range all {
+		p.Add(pokemon)
+	}
+	if err := p.Save(); err != nil {
+		logger.Error("Error importing pokedex into store", "path", path, "error", err)
+		return
+	}
+	logger.Info("Imported pokedex into store", "path", path, "pokemon", len(all))
+}
+
+func syncPolicy(name string) kvstore.SyncPolicy {
+	switch name {
+	case "always":
+		return kvstore.SyncAlways
+	case "never":
+		return kvstore.SyncNever
+	default:
+		return kvstore.SyncInterval
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flarenzy/Pokedex/internal/domain"
+	"github.com/Flarenzy/Pokedex/internal/kvstore"
+	"github.com/Flarenzy/Pokedex/internal/pokedex"
+)
+
+func TestOpenStorage(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name       string
+		backend    string
+		legacySave bool
+		wantStore  bool
+		wantFile   string
+	}{
+		{name: "files by default", wantFile: "pokedex.json"},
+		{name: "kv store", backend: "kv", wantStore: true, wantFile: "pokedex.db"},
+		{name: "kv store imports json save", backend: "kv", legacySave: true, wantStore: true, wantFile: "pokedex.db"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if tc.legacySave {
+				legacy := pokedex.NewFilePokedex(filepath.Join(dir, "pokedex.json"))
+				legacy.Add(pokedex.Pokemon{Name: "mew"})
+				if err := legacy.Save(); err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+			}
+
+			s, err := openStorage(logger, dir, tc.backend)
+			if err != nil {
+				t.Fatalf("expected storage, got %v", err)
+			}
+			if (s.store != nil) != tc.wantStore {
+				t.Fatalf("expected store=%v, got %v", tc.wantStore, s.store)
+			}
+			if s.cache == nil {
+				t.Fatal("expected a persistent cache layer")
+			}
+			if _, err := s.pokedex.GetPokemonByName("mew"); (err == nil) != tc.legacySave {
+				t.Fatalf("unexpected pokedex contents, got error %v", err)
+			}
+			s.pokedex.Add(pokedex.Pokemon{Name: "pikachu"})
+			if err = s.pokedex.(domain.Saver).Save(); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if err = s.cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte("{}")); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			s.cache.Done()
+			if err = s.Close(); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if _, err = os.Stat(filepath.Join(dir, tc.wantFile)); err != nil {
+				t.Fatalf("expected %s to be written, got %v", tc.wantFile, err)
+			}
+
+			reopened, err := openStorage(logger, dir, tc.backend)
+			if err != nil {
+				t.Fatalf("expected storage, got %v", err)
+			}
+			defer reopened.Close()
+			defer reopened.cache.Done()
+			if _, err := reopened.pokedex.GetPokemonByName("pikachu"); err != nil {
+				t.Fatalf("expected saved pokemon after reopen, got %v", err)
+			}
+			if _, err := reopened.cache.Get("https://pokeapi.co/api/v2/pokemon/pikachu"); err != nil {
+				t.Fatalf("expected cached body after reopen, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenStorageDoesNotFallBackFromKV(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pokedex.db")
+
+	s, err := openStorage(logger, dir, "kv")
+	if err != nil {
+		t.Fatalf("expected storage, got %v", err)
+	}
+	for _, name := range []string{"mew", "pikachu", "psyduck"} {
+		s.pokedex.Add(pokedex.Pokemon{Name: name})
+	}
+	if err = s.pokedex.(domain.Saver).Save(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err = openStorage(logger, dir, "kv"); !errors.Is(err, kvstore.ErrLocked) {
+		t.Fatalf("expected ErrLocked while the store is open, got %v", err)
+	}
+	s.cache.Done()
+	if err = s.Close(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	good, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	corrupt := bytes.Clone(good)
+	corrupt[len(corrupt)/4] ^= 0xff
+	if err = os.WriteFile(path, corrupt, 0o644); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err = openStorage(logger, dir, "kv"); !errors.Is(err, kvstore.ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "pokedex.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no fallback save file, got %v", err)
+	}
+
+	if err = os.WriteFile(path, good, 0o644); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	repaired, err := openStorage(logger, dir, "kv")
+	if err != nil {
+		t.Fatalf("expected the repaired store to open, got %v", err)
+	}
+	defer repaired.Close()
+	defer repaired.cache.Done()
+	if got := len(repaired.pokedex.GetAllPokemon()); got != 3 {
+		t.Fatalf("expected 3 pokemon after the repair, got %d", got)
+	}
+}
+
+func TestSyncPolicy(t *testing.T) {
+	t.Parallel()
+	tests := map[string]kvstore.SyncPolicy{
+		"always":   kvstore.SyncAlways,
+		"never":    kvstore.SyncNever,
+		"interval": kvstore.SyncInterval,
+		"":         kvstore.SyncInterval,
+	}
+	for name, want := range tests {
+		if got := syncPolicy(name); got != want {
+			t.Fatalf("syncPolicy(%q): expected %v, got %v", name, want, got)
+		}
+	}
+}